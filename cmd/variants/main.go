@@ -0,0 +1,122 @@
+// variants 生成敏感词的候选变体, 供人工审核后批量加入字典
+//
+// 用法:
+//
+//	variants [-kinds pinyin,leet] [-pinyin pinyin.txt] [-tradsimp tradsimp.txt] [-shape shape.txt] [-split split.txt] [-kind] [敏感词...]
+//
+// 没有指定敏感词时从标准输入逐行读取, 每行输出一个变体, 可以直接作为字典文件加载
+// 对照表文件的格式见 dict.LoadTable, 简繁对照表会自动加入反向对照
+// 没有指定 -kinds 时只生成提供了对照表的种类与内置了默认值的 leet, noise, 一个对照表都没有提供时会在标准错误中提示
+// 指定的种类缺少对照表时报错退出
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func main() {
+	kinds := flag.String("kinds", "", "comma separated variant kinds, by default the kinds whose table is given plus the built-in leet and noise")
+	tradSimp := flag.String("tradsimp", "", "traditional/simplified chinese table file")
+	pinyin := flag.String("pinyin", "", "pinyin table file, also used for initials and homophones")
+	shape := flag.String("shape", "", "shape-similar characters table file")
+	split := flag.String("split", "", "split characters table file")
+	noise := flag.String("noise", "", "comma separated noise strings, dict.DefaultNoise by default")
+	max := flag.Int("max", 0, "max variants per word, 0 means no limit")
+	showKind := flag.Bool("kind", false, "print the variant kind after each variant for review, the output is no longer a dict file")
+	flag.Parse()
+
+	option := dict.VariantOption{
+		Max: *max,
+	}
+
+	for _, kind := range strings.Split(*kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			option.Kinds = append(option.Kinds, dict.VariantKind(kind))
+		}
+	}
+	if *noise != "" {
+		option.Noise = strings.Split(*noise, ",")
+	}
+
+	for _, table := range []struct {
+		path   string
+		target *dict.Table
+	}{
+		{*tradSimp, &option.TradSimp},
+		{*pinyin, &option.Pinyin},
+		{*shape, &option.Shape},
+		{*split, &option.Split},
+	} {
+		if table.path == "" {
+			continue
+		}
+
+		t, err := loadTable(table.path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		*table.target = t
+	}
+	if option.TradSimp != nil {
+		option.TradSimp = option.TradSimp.Symmetric()
+	}
+	if len(option.Kinds) == 0 && option.TradSimp == nil && option.Pinyin == nil && option.Shape == nil && option.Split == nil {
+		fmt.Fprintln(os.Stderr, "no table file given, only leet and noise variants are generated")
+	}
+
+	variator, err := dict.NewVariator(option)
+	if errors.Is(err, dict.ErrMissingTable) {
+		fmt.Fprintf(os.Stderr, "%v, provide the table file with the flag of the same name (pinyin for initials and homophone)\n", err)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v, available kinds: %v\n", err, dict.VariantKinds)
+		os.Exit(2)
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	generate := func(word string) {
+		for _, variant := range variator.Variants(word) {
+			if *showKind {
+				fmt.Fprintf(writer, "%s\t# %s\n", variant.Word, variant.Kind)
+			} else {
+				fmt.Fprintln(writer, variant.Word)
+			}
+		}
+	}
+
+	if flag.NArg() > 0 {
+		for _, word := range flag.Args() {
+			generate(word)
+		}
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			generate(word)
+		}
+	}
+}
+
+func loadTable(path string) (dict.Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	return dict.LoadTable(f)
+}
@@ -0,0 +1,119 @@
+package dict
+
+import (
+	"strconv"
+	"strings"
+)
+
+const DefaultWeight = 1
+
+// Entry 敏感词及其分类(如 politics, porn, ads, abuse)
+type Entry struct {
+	Word        string
+	Categories  []string
+	Weight      float64 // 严重程度, 用于计算文本风险分数, 未设置时为 DefaultWeight
+	Replacement string  // 替换文本, 为空时使用掩码替换
+	Source      string  // 敏感词的来源, 如字典文件路径, 字典 url 或数据源名称, 不会持久化到数据源中
+	MaxGap      int     // 相邻两个文字之间最多可以出现的任意文字数量, 为 0 时使用过滤器的设置
+	MaxSpan     int     // 允许间隔时匹配到的文本的最大长度, 为 0 时使用过滤器的设置
+	Stem        bool    // 英文敏感词同时匹配常见的屈折变化(如 "kill" 匹配 "kills", "killed", "killing"), 且只在单词边界上匹配
+}
+
+// ParseEntry 解析字典中的一行, 格式为 "敏感词[\t分类1,分类2[\t权重[\t替换文本[\t选项]]]]", 选项的格式见 ParseOptions
+func ParseEntry(line string) Entry {
+	fields := strings.Split(line, "\t")
+
+	entry := Entry{
+		Word: fields[0],
+	}
+
+	if len(fields) > 1 {
+		entry.Categories = SplitCategories(fields[1])
+	}
+
+	if len(fields) > 2 {
+		entry.Weight, _ = strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+	}
+
+	if len(fields) > 3 {
+		entry.Replacement = fields[3]
+	}
+
+	if len(fields) > 4 {
+		entry.ParseOptions(fields[4])
+	}
+
+	return entry
+}
+
+// SplitCategories 拆分以逗号分隔的分类
+func SplitCategories(categories string) []string {
+	var res []string
+
+	for _, category := range strings.Split(categories, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			res = append(res, category)
+		}
+	}
+
+	return res
+}
+
+// ParseOptions 解析以逗号分隔的选项, 如 "gap=2,span=8,stem", 忽略无法识别的选项
+func (e *Entry) ParseOptions(options string) {
+	for _, option := range strings.Split(options, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+
+		switch key {
+		case "gap":
+			e.MaxGap, _ = strconv.Atoi(value)
+		case "span":
+			e.MaxSpan, _ = strconv.Atoi(value)
+		case "stem":
+			e.Stem = true
+		}
+	}
+}
+
+// FormatOptions 将选项格式化为 ParseOptions 可以解析的字符串, 用于持久化到数据源中
+func (e *Entry) FormatOptions() string {
+	var options []string
+
+	if e.MaxGap != 0 {
+		options = append(options, "gap="+strconv.Itoa(e.MaxGap))
+	}
+	if e.MaxSpan != 0 {
+		options = append(options, "span="+strconv.Itoa(e.MaxSpan))
+	}
+	if e.Stem {
+		options = append(options, "stem")
+	}
+
+	return strings.Join(options, ",")
+}
+
+// HasCategory 敏感词是否属于任意一个指定的分类, 未指定分类时总是返回 true
+func (e *Entry) HasCategory(categories ...string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+
+	for _, category := range categories {
+		for _, c := range e.Categories {
+			if c == category {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GetWeight 返回敏感词的权重, 未设置时返回 DefaultWeight
+func (e *Entry) GetWeight() float64 {
+	if e.Weight == 0 {
+		return DefaultWeight
+	}
+
+	return e.Weight
+}
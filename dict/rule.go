@@ -0,0 +1,273 @@
+package dict
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrEmptyRule = errors.New("empty rule")
+
+// Rule 由字典中的敏感词组成的布尔规则, 如 "(赌博 OR 博彩) AND (充值 OR 返利) AND NOT 反诈"
+// 运算符优先级从高到低为 NOT, AND, OR, 包含空白, 括号或引号的敏感词可以用双引号括起来
+type Rule struct {
+	Name   string
+	Expr   string
+	Source string // 规则的来源, 如规则文件路径, 规则 url 或数据源名称, 不会持久化到数据源中
+}
+
+// ParseRule 解析规则文件中的一行, 格式为 "规则名\t表达式"
+func ParseRule(line string) (Rule, error) {
+	fields := strings.SplitN(line, "\t", 2)
+	if len(fields) != 2 || strings.TrimSpace(fields[0]) == "" {
+		return Rule{}, fmt.Errorf("invalid rule line %q", line)
+	}
+
+	rule := Rule{
+		Name: strings.TrimSpace(fields[0]),
+		Expr: strings.TrimSpace(fields[1]),
+	}
+
+	if _, err := CompileExpr(rule.Expr); err != nil {
+		return Rule{}, fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+
+	return rule, nil
+}
+
+// Expr 编译后的规则表达式
+type Expr interface {
+	// Eval 计算表达式, has 返回敏感词是否出现在文本中
+	Eval(has func(term string) bool) bool
+	// Terms 返回表达式中的所有敏感词
+	Terms() []string
+}
+
+type (
+	termExpr string
+	notExpr  struct{ expr Expr }
+	andExpr  []Expr
+	orExpr   []Expr
+)
+
+func (e termExpr) Eval(has func(term string) bool) bool {
+	return has(string(e))
+}
+
+func (e termExpr) Terms() []string {
+	return []string{string(e)}
+}
+
+func (e notExpr) Eval(has func(term string) bool) bool {
+	return !e.expr.Eval(has)
+}
+
+func (e notExpr) Terms() []string {
+	return e.expr.Terms()
+}
+
+func (e andExpr) Eval(has func(term string) bool) bool {
+	for _, expr := range e {
+		if !expr.Eval(has) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e andExpr) Terms() []string {
+	return joinTerms(e)
+}
+
+func (e orExpr) Eval(has func(term string) bool) bool {
+	for _, expr := range e {
+		if expr.Eval(has) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e orExpr) Terms() []string {
+	return joinTerms(e)
+}
+
+func joinTerms(exprs []Expr) []string {
+	var res []string
+
+	for _, expr := range exprs {
+		res = append(res, expr.Terms()...)
+	}
+
+	return res
+}
+
+// CompileExpr 编译规则表达式
+func CompileExpr(expr string) (Expr, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmptyRule
+	}
+
+	p := &ruleParser{tokens: tokens}
+
+	res, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	return res, nil
+}
+
+type ruleToken struct {
+	text   string
+	quoted bool // 用双引号括起来的敏感词, 不会被当作运算符或括号
+}
+
+func tokenize(expr string) ([]ruleToken, error) {
+	var res []ruleToken
+
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			res = append(res, ruleToken{text: string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated quote")
+			}
+			if end == i+1 {
+				return nil, errors.New("empty quoted term")
+			}
+			res = append(res, ruleToken{text: string(runes[i+1 : end]), quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			res = append(res, ruleToken{text: string(runes[i:end])})
+			i = end
+		}
+	}
+
+	return res, nil
+}
+
+// ruleParser 递归下降解析
+//
+//	or    = and { "OR" and }
+//	and   = unary { "AND" unary }
+//	unary = "NOT" unary | "(" or ")" | term
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+}
+
+// accept 下一个符号为运算符或括号 op 时跳过它
+func (p *ruleParser) accept(op string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && p.tokens[p.pos].text == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *ruleParser) parseOr() (Expr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	res := orExpr{expr}
+	for p.accept("OR") {
+		if expr, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+		res = append(res, expr)
+	}
+
+	if len(res) == 1 {
+		return res[0], nil
+	}
+
+	return res, nil
+}
+
+func (p *ruleParser) parseAnd() (Expr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	res := andExpr{expr}
+	for p.accept("AND") {
+		if expr, err = p.parseUnary(); err != nil {
+			return nil, err
+		}
+		res = append(res, expr)
+	}
+
+	if len(res) == 1 {
+		return res[0], nil
+	}
+
+	return res, nil
+}
+
+func (p *ruleParser) parseUnary() (Expr, error) {
+	if p.accept("NOT") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{expr}, nil
+	}
+
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, errors.New("missing )")
+		}
+
+		return expr, nil
+	}
+
+	if p.pos == len(p.tokens) {
+		return nil, errors.New("unexpected end of rule")
+	}
+
+	token := p.tokens[p.pos]
+	if !token.quoted {
+		switch token.text {
+		case "AND", "OR", "NOT", "(", ")":
+			return nil, fmt.Errorf("unexpected %q", token.text)
+		}
+	}
+	p.pos++
+
+	return termExpr(token.text), nil
+}
@@ -0,0 +1,358 @@
+package dict
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// VariantKind 变体的种类
+type VariantKind string
+
+const (
+	VariantTradSimp  VariantKind = "tradsimp"  // 简繁转换
+	VariantPinyin    VariantKind = "pinyin"    // 拼音, 如 "minganci", "敏gan词"
+	VariantInitials  VariantKind = "initials"  // 拼音首字母, 如 "mgc"
+	VariantHomophone VariantKind = "homophone" // 同音字
+	VariantShape     VariantKind = "shape"     // 形近字
+	VariantSplit     VariantKind = "split"     // 拆字, 如 "好" -> "女子"
+	VariantLeet      VariantKind = "leet"      // leet 写法, 如 "shit" -> "5h1t"
+	VariantNoise     VariantKind = "noise"     // 插入干扰字符, 如 "敏*感*词"
+)
+
+// VariantKinds 所有变体种类
+var VariantKinds = []VariantKind{
+	VariantTradSimp, VariantPinyin, VariantInitials, VariantHomophone,
+	VariantShape, VariantSplit, VariantLeet, VariantNoise,
+}
+
+var (
+	// DefaultLeet 默认的 leet 对照表
+	DefaultLeet = Table{
+		'a': {"4", "@"},
+		'b': {"8"},
+		'e': {"3"},
+		'g': {"9"},
+		'i': {"1", "!"},
+		'l': {"1"},
+		'o': {"0"},
+		's': {"5", "$"},
+		't': {"7"},
+		'z': {"2"},
+	}
+	// DefaultNoise 默认的干扰字符
+	DefaultNoise = []string{"*", ".", "_", "-", " "}
+)
+
+// ErrMissingTable 指定的变体种类没有对照表
+var ErrMissingTable = errors.New("missing variant table")
+
+// Table 文字对照表, 每个文字对应若干个候选文本
+type Table map[rune][]string
+
+// LoadTable 加载对照表, 每行格式为 "文字\t候选1,候选2", 跳过空行与 # 开头的注释, 同一个文字出现多次时合并候选
+func LoadTable(reader io.Reader) (Table, error) {
+	res := make(Table)
+
+	buf := bufio.NewReader(reader)
+	for {
+		line, _, err := buf.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		text := strings.TrimSpace(string(line))
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, values, _ := strings.Cut(text, "\t")
+		runes := []rune(strings.TrimSpace(key))
+		if len(runes) != 1 {
+			continue
+		}
+
+		for _, value := range strings.Split(values, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				res.add(runes[0], value)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (t Table) add(r rune, value string) {
+	for _, v := range t[r] {
+		if v == value {
+			return
+		}
+	}
+
+	t[r] = append(t[r], value)
+}
+
+// keys 返回排序后的所有文字, 使生成的变体顺序固定
+func (t Table) keys() []rune {
+	res := make([]rune, 0, len(t))
+	for r := range t {
+		res = append(res, r)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+
+	return res
+}
+
+// Symmetric 返回加入反向对照后的对照表, 用于简繁对照等双向的对照表
+func (t Table) Symmetric() Table {
+	res := make(Table, len(t))
+
+	for _, r := range t.keys() {
+		for _, value := range t[r] {
+			res.add(r, value)
+			if runes := []rune(value); len(runes) == 1 {
+				res.add(runes[0], string(r))
+			}
+		}
+	}
+
+	return res
+}
+
+// VariantOption 生成变体的选项
+// 简繁, 拼音, 形近字与拆字需要提供对照表, 拼音首字母与同音字由拼音对照表得到, Kinds 中的种类没有对照表时 NewVariator 返回 ErrMissingTable
+type VariantOption struct {
+	Kinds    []VariantKind // 生成的变体种类, 为空时生成所有提供了对照表的种类
+	TradSimp Table         // 简繁对照
+	Pinyin   Table         // 汉字 -> 不带声调的拼音, 多音字有多个候选
+	Shape    Table         // 形近字
+	Split    Table         // 拆字
+	Leet     Table         // 为 nil 时使用 DefaultLeet
+	Noise    []string      // 为空时使用 DefaultNoise
+	Max      int           // 最多返回的变体数量, 为 0 时不限制
+}
+
+// Variant 敏感词的一个候选变体
+type Variant struct {
+	Word string
+	Kind VariantKind
+}
+
+// Variator 生成敏感词的候选变体, 供人工审核后批量加入字典
+type Variator struct {
+	option     VariantOption
+	initials   Table
+	homophones Table
+}
+
+func NewVariator(option VariantOption) (*Variator, error) {
+	if option.Leet == nil {
+		option.Leet = DefaultLeet
+	}
+	if len(option.Noise) == 0 {
+		option.Noise = DefaultNoise
+	}
+
+	if len(option.Kinds) == 0 {
+		for _, kind := range VariantKinds {
+			if ok, _ := option.provided(kind); ok {
+				option.Kinds = append(option.Kinds, kind)
+			}
+		}
+	}
+
+	for _, kind := range option.Kinds {
+		ok, err := option.provided(kind)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", kind, ErrMissingTable)
+		}
+	}
+
+	v := &Variator{
+		option:     option,
+		initials:   initials(option.Pinyin),
+		homophones: make(Table),
+	}
+
+	// 拼音相同的汉字互为同音字
+	syllables := make(map[string][]rune)
+	for _, r := range option.Pinyin.keys() {
+		for _, syllable := range option.Pinyin[r] {
+			syllables[syllable] = append(syllables[syllable], r)
+		}
+	}
+	for r, values := range option.Pinyin {
+		for _, syllable := range values {
+			for _, homophone := range syllables[syllable] {
+				if homophone != r {
+					v.homophones.add(r, string(homophone))
+				}
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// provided 返回生成 kind 需要的对照表是否已经提供, kind 未知时返回错误
+func (o *VariantOption) provided(kind VariantKind) (bool, error) {
+	switch kind {
+	case VariantTradSimp:
+		return len(o.TradSimp) > 0, nil
+	case VariantPinyin, VariantInitials, VariantHomophone:
+		return len(o.Pinyin) > 0, nil
+	case VariantShape:
+		return len(o.Shape) > 0, nil
+	case VariantSplit:
+		return len(o.Split) > 0, nil
+	case VariantLeet:
+		return len(o.Leet) > 0, nil
+	case VariantNoise:
+		return len(o.Noise) > 0, nil
+	}
+
+	return false, fmt.Errorf("unknown variant kind %q", kind)
+}
+
+// Variants 使用 option 生成 word 的候选变体
+func Variants(word string, option VariantOption) ([]Variant, error) {
+	v, err := NewVariator(option)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Variants(word), nil
+}
+
+// Variants 生成 word 的候选变体, 结果去重且不包含 word 本身
+func (v *Variator) Variants(word string) []Variant {
+	var res []Variant
+	set := map[string]struct{}{word: {}}
+
+	add := func(kind VariantKind, words ...string) bool {
+		for _, w := range words {
+			if v.option.Max > 0 && len(res) >= v.option.Max {
+				return false
+			}
+			if _, ok := set[w]; !ok {
+				set[w] = struct{}{}
+				res = append(res, Variant{Word: w, Kind: kind})
+			}
+		}
+		return true
+	}
+
+	runes := []rune(word)
+
+	for _, kind := range v.option.Kinds {
+		var words []string
+
+		switch kind {
+		case VariantTradSimp:
+			words = substitute(runes, v.option.TradSimp)
+		case VariantPinyin:
+			words = substitute(runes, v.option.Pinyin)
+		case VariantInitials:
+			words = substitute(runes, v.initials)
+		case VariantHomophone:
+			words = substitute(runes, v.homophones)
+		case VariantShape:
+			words = substitute(runes, v.option.Shape)
+		case VariantSplit:
+			words = substitute(runes, v.option.Split)
+		case VariantLeet:
+			words = substitute([]rune(strings.ToLower(word)), v.option.Leet)
+		case VariantNoise:
+			words = insertNoise(runes, v.option.Noise)
+		}
+
+		if !add(kind, words...) {
+			break
+		}
+	}
+
+	return res
+}
+
+// substitute 先返回所有文字都替换为第一个候选的变体, 再返回每次只替换一个文字的变体
+func substitute(runes []rune, table Table) []string {
+	if len(table) == 0 {
+		return nil
+	}
+
+	var res []string
+
+	var builder strings.Builder
+	for _, r := range runes {
+		if values, ok := table[r]; ok {
+			builder.WriteString(values[0])
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	res = append(res, builder.String())
+
+	for i, r := range runes {
+		for _, value := range table[r] {
+			res = append(res, string(runes[:i])+value+string(runes[i+1:]))
+		}
+	}
+
+	return res
+}
+
+// initials 由拼音对照表得到拼音首字母对照表
+func initials(pinyin Table) Table {
+	res := make(Table, len(pinyin))
+
+	for r, values := range pinyin {
+		for _, value := range values {
+			for _, c := range value {
+				if unicode.IsLetter(c) {
+					res.add(r, string(c))
+				}
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// insertNoise 先返回每两个文字之间都插入干扰字符的变体, 再返回只插入一处干扰字符的变体
+func insertNoise(runes []rune, noise []string) []string {
+	if len(runes) < 2 {
+		return nil
+	}
+
+	var res []string
+
+	for _, n := range noise {
+		parts := make([]string, len(runes))
+		for i, r := range runes {
+			parts[i] = string(r)
+		}
+		res = append(res, strings.Join(parts, n))
+	}
+
+	for i := 1; i < len(runes); i++ {
+		for _, n := range noise {
+			res = append(res, string(runes[:i])+n+string(runes[i:]))
+		}
+	}
+
+	return res
+}
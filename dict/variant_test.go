@@ -0,0 +1,119 @@
+package dict
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Variants(t *testing.T) {
+	pinyin, err := LoadTable(strings.NewReader("# 拼音\n敏\tmin\n感\tgan\n词\tci\n赶\tgan\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		word   string
+		option VariantOption
+		result []string
+	}{
+		{
+			name:   "tradsimp",
+			word:   "敏感词",
+			option: VariantOption{Kinds: []VariantKind{VariantTradSimp}, TradSimp: Table{'词': {"詞"}}.Symmetric()},
+			result: []string{"敏感詞"},
+		},
+		{
+			name:   "pinyin",
+			word:   "敏感词",
+			option: VariantOption{Kinds: []VariantKind{VariantPinyin}, Pinyin: pinyin},
+			result: []string{"minganci", "min感词", "敏gan词", "敏感ci"},
+		},
+		{
+			name:   "initials",
+			word:   "敏感词",
+			option: VariantOption{Kinds: []VariantKind{VariantInitials}, Pinyin: pinyin},
+			result: []string{"mgc", "m感词", "敏g词", "敏感c"},
+		},
+		{
+			name:   "homophone",
+			word:   "敏感词",
+			option: VariantOption{Kinds: []VariantKind{VariantHomophone}, Pinyin: pinyin},
+			result: []string{"敏赶词"},
+		},
+		{
+			name:   "split",
+			word:   "好人",
+			option: VariantOption{Kinds: []VariantKind{VariantSplit}, Split: Table{'好': {"女子"}}},
+			result: []string{"女子人"},
+		},
+		{
+			name:   "leet",
+			word:   "Shit",
+			option: VariantOption{Kinds: []VariantKind{VariantLeet}, Leet: Table{'s': {"5"}, 'i': {"1", "!"}}},
+			result: []string{"5h1t", "5hit", "sh1t", "sh!t"},
+		},
+		{
+			name:   "noise",
+			word:   "敏感词",
+			option: VariantOption{Kinds: []VariantKind{VariantNoise}, Noise: []string{"*"}},
+			result: []string{"敏*感*词", "敏*感词", "敏感*词"},
+		},
+		{
+			name:   "max",
+			word:   "敏感词",
+			option: VariantOption{Noise: []string{"*"}, Pinyin: pinyin, Max: 2},
+			result: []string{"minganci", "min感词"},
+		},
+		{
+			name:   "default kinds",
+			word:   "敏感",
+			option: VariantOption{Noise: []string{"*"}, Leet: Table{}},
+			result: []string{"敏*感"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := Variants(tt.word, tt.option)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result []string
+			for _, variant := range variants {
+				result = append(result, variant.Word)
+			}
+
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("Variants() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
+
+func Test_NewVariator(t *testing.T) {
+	tests := []struct {
+		name   string
+		option VariantOption
+		err    error
+	}{
+		{name: "default", option: VariantOption{}},
+		{name: "no table", option: VariantOption{Kinds: []VariantKind{VariantShape}}, err: ErrMissingTable},
+		{name: "no pinyin", option: VariantOption{Kinds: []VariantKind{VariantHomophone}}, err: ErrMissingTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVariator(tt.option); !errors.Is(err, tt.err) {
+				t.Errorf("NewVariator() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := NewVariator(VariantOption{Kinds: []VariantKind{"unknown"}}); err == nil {
+		t.Errorf("NewVariator() error = nil, want unknown kind")
+	}
+}
@@ -1,87 +1,232 @@
 package filter
 
 import (
+	"io"
+	"unicode/utf8"
+	"unsafe"
+
 	"github.com/sgoware/ds/queue/arrayqueue"
+	"github.com/sgoware/go-sensitive/dict"
 )
 
 type acNode struct {
 	value    rune
-	children map[rune]*acNode
-	word     *string
+	children nodeChildren[*acNode]
+	entry    *dict.Entry
+	depth    int
 	fail     *acNode
 }
 
-func newAcNode(r rune) *acNode {
+func newAcNode(r rune, depth int) *acNode {
 	return &acNode{
-		value:    r,
-		children: make(map[rune]*acNode),
-		word:     nil,
+		value: r,
+		depth: depth,
+		entry: nil,
+	}
+}
+
+func (n *acNode) next(r rune) (*acNode, bool) {
+	return n.children.get(r)
+}
+
+func (n *acNode) leaf() *dict.Entry {
+	return n.entry
+}
+
+// walk 遍历结点及其子结点上的敏感词, 词形扩展与敏感词本身会重复出现
+func (n *acNode) walk(fn func(entry *dict.Entry)) {
+	if entry := n.leaf(); entry != nil {
+		fn(entry)
+	}
+
+	n.children.each(func(_ rune, child *acNode) {
+		child.walk(fn)
+	})
+}
+
+// stats 统计结点及其所有子结点
+func (n *acNode) stats(stats *Stats) {
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*n)) + n.children.bytes()
+	if n.children.dense {
+		stats.DenseNodes++
 	}
+
+	n.children.each(func(_ rune, child *acNode) {
+		child.stats(stats)
+	})
 }
 
 type AcModel struct {
-	root *acNode
+	root        *acNode
+	normalizers normalizers
+	gap         GapOption
+	maxGap      int // 过滤器与所有敏感词中最大的间隔
+	spans       spanLimit
+	longest     int // 最长的敏感词规范化后的文字数量, 删除敏感词时不减小
+	phonetic    *phoneticIndex
 }
 
-func NewAcModel() *AcModel {
+// NewAcModel 创建 AC 自动机过滤器, 敏感词和文本在匹配前会依次经过 normalizers 规范化
+func NewAcModel(normalizers ...Normalizer) *AcModel {
 	return &AcModel{
-		root: newAcNode(0),
+		root:        newAcNode(0, 0),
+		normalizers: normalizers,
+	}
+}
+
+// SetGap 设置所有敏感词相邻文字之间允许的间隔
+func (m *AcModel) SetGap(option GapOption) {
+	m.gap = option
+	if option.MaxGap > m.maxGap {
+		m.maxGap = option.MaxGap
+	}
+}
+
+// SetPhonetic 启用或关闭语音匹配, 为拉丁字母组成的敏感词建立 Double Metaphone 索引
+// 读音相同但拼写不同的单词以 PhoneticConfidence 或 PhoneticAlternateConfidence 的可信度返回, 只有 FindMatches 与 Explain 等返回可信度的接口使用
+func (m *AcModel) SetPhonetic(enabled bool) {
+	if !enabled {
+		m.phonetic = nil
+		return
+	}
+
+	if m.phonetic == nil {
+		m.phonetic = newPhoneticIndex()
+		m.root.walk(m.phonetic.add)
 	}
 }
 
 func (m *AcModel) AddWords(words ...string) {
 	for _, word := range words {
-		m.AddWord(word)
+		m.addEntry(dict.Entry{Word: word})
 	}
 
 	m.buildFailPointers()
 }
 
 func (m *AcModel) AddWord(word string) {
+	m.AddEntry(dict.Entry{Word: word})
+}
+
+func (m *AcModel) AddEntries(entries ...dict.Entry) {
+	for _, entry := range entries {
+		m.addEntry(entry)
+	}
+
+	m.buildFailPointers()
+}
+
+func (m *AcModel) AddEntry(entry dict.Entry) {
+	m.addEntry(entry)
+	m.buildFailPointers()
+}
+
+// addEntry 加入敏感词但不重新构建失败指针, 批量修改后只构建一次
+func (m *AcModel) addEntry(entry dict.Entry) {
+	// 替换已有的敏感词时一并删除它的词形扩展
+	m.delWord(entry.Word)
+
+	m.insert(entry.Word, &entry)
+	for _, form := range inflections(&entry) {
+		m.insert(form, &entry)
+	}
+
+	if m.phonetic != nil {
+		m.phonetic.add(&entry)
+	}
+
+	if entry.MaxGap > m.maxGap {
+		m.maxGap = entry.MaxGap
+	}
+	m.spans.add(&entry)
+}
+
+// insert 将 word 插入字典树, 词形扩展不会覆盖字典中的同名敏感词
+func (m *AcModel) insert(word string, entry *dict.Entry) {
 	now := m.root
-	runes := []rune(word)
+	runes, _ := m.normalizers.normalize([]rune(word))
+
+	if len(runes) > m.longest {
+		m.longest = len(runes)
+	}
 
 	for _, r := range runes {
-		if next, ok := now.children[r]; ok {
+		if next, ok := now.next(r); ok {
 			now = next
 		} else {
-			next = newAcNode(r)
-			now.children[r] = next
+			next = newAcNode(r, now.depth+1)
+			now.children.set(r, next)
 			now = next
 		}
 	}
 
-	now.word = new(string)
-	*now.word = word
+	if leaf := now.leaf(); leaf != nil && leaf.Word == word && entry.Word != word {
+		return
+	}
+
+	now.entry = entry
 }
 
 func (m *AcModel) DelWords(words ...string) {
 	for _, word := range words {
-		m.DelWord(word)
+		m.delWord(word)
 	}
 
 	m.buildFailPointers()
 }
 
 func (m *AcModel) DelWord(word string) {
-	var lastLeaf *acNode
-	var lastLeafNextRune rune
+	m.delWord(word)
+	m.buildFailPointers()
+}
+
+// delWord 删除敏感词但不重新构建失败指针
+func (m *AcModel) delWord(word string) {
+	// 删除的是其他敏感词的词形扩展时, 不删除其他敏感词
+	entry := m.remove(word, nil)
+	if entry == nil || entry.Word != word {
+		return
+	}
+
+	if m.phonetic != nil {
+		m.phonetic.remove(entry)
+	}
+
+	for _, form := range inflections(entry) {
+		m.remove(form, entry)
+	}
+}
+
+// remove 从字典树中删除 word, owner 不为 nil 时只删除属于 owner 的词形扩展, 返回被删除的敏感词
+func (m *AcModel) remove(word string, owner *dict.Entry) *dict.Entry {
 	now := m.root
-	runes := []rune(word)
+	runes, _ := m.normalizers.normalize([]rune(word))
+	path := make([]*acNode, 0, len(runes)+1)
 
 	for _, r := range runes {
-		if next, ok := now.children[r]; !ok {
-			return
-		} else {
-			if now.word != nil {
-				lastLeaf = now
-				lastLeafNextRune = r
-			}
-			now = next
+		next, ok := now.next(r)
+		if !ok {
+			return nil
 		}
+		path = append(path, now)
+		now = next
+	}
+
+	entry := now.leaf()
+	if owner != nil && entry != owner {
+		return nil
 	}
 
-	delete(lastLeaf.children, lastLeafNextRune)
+	now.entry = nil
+
+	// 从叶子结点向上删除不再属于任何敏感词的结点
+	for i := len(runes) - 1; i >= 0 && now.entry == nil && now.children.len() == 0; i-- {
+		path[i].children.del(runes[i])
+		now = path[i]
+	}
+
+	return entry
 }
 
 func (m *AcModel) buildFailPointers() {
@@ -90,13 +235,13 @@ func (m *AcModel) buildFailPointers() {
 	for q.Len() > 0 {
 		temp, _ := q.Top()
 		q.Pop()
-		for _, node := range temp.(*acNode).children {
+		temp.(*acNode).children.each(func(_ rune, node *acNode) {
 			if temp.(*acNode) == m.root {
 				node.fail = m.root
 			} else {
 				p := temp.(*acNode).fail
 				for p != nil {
-					if next, found := p.children[node.value]; found {
+					if next, found := p.next(node.value); found {
 						node.fail = next
 						break
 					}
@@ -108,55 +253,50 @@ func (m *AcModel) buildFailPointers() {
 			}
 
 			q.Push(node)
-		}
+		})
 	}
 }
 
-func (m *AcModel) Listen(addChan, delChan <-chan string) {
-	go func() {
-		var words []string
-
-		for word := range addChan {
-			words = append(words, word)
-			if len(addChan) == 0 {
-				m.AddWords(words...)
-				word = word[:0]
-			}
-		}
-	}()
+// Listen 监听数据源中敏感词的变化, 每 listenInterval 按读到的顺序批量修改一次, 每批只重新计算一次失败指针, 见 listen
+func (m *AcModel) Listen(addChan <-chan dict.Entry, delChan <-chan string) {
+	go listen(addChan, delChan, listenInterval, func(entries []dict.Entry) {
+		m.AddEntries(entries...)
+	}, func(words []string) {
+		m.DelWords(words...)
+	})
+}
 
-	go func() {
-		var words []string
+// scan 先使用自动机查找连续的敏感词, 允许间隔时再在字典树中查找有间隔的敏感词, 启用语音匹配时最后查找读音相同的敏感词
+func (m *AcModel) scan(runes []rune, fn scanFunc) {
+	fn = bounded(runes, fn)
 
-		for word := range delChan {
-			words = append(words, word)
-			if len(delChan) == 0 {
-				m.DelWords(words...)
-				word = word[:0]
-			}
-		}
-	}()
+	if !m.scanAutomaton(runes, fn) {
+		return
+	}
+	if m.maxGap > 0 && !gapScan(m.root, runes, m.gap, m.maxGap, m.spans.bound(m.gap), true, fn) {
+		return
+	}
+	if m.phonetic != nil {
+		m.phonetic.scan(runes, fn)
+	}
 }
 
-func (m *AcModel) FindAll(text string) []string {
-	var matches []string
-	var found bool
+func (m *AcModel) scanAutomaton(runes []rune, fn scanFunc) bool {
+	var temp *acNode
 
 	now := m.root
-	var temp *acNode
-	runes := []rune(text)
 
 	for pos := 0; pos < len(runes); pos++ {
-		_, found = now.children[runes[pos]]
-		if !found && now != m.root {
-			now = now.fail
-			for ; !found && now != m.root; now, found = now.children[runes[pos]] {
-				now = now.fail
+		// 沿失败指针回退, 直到找到可以接受当前字符的结点或回到根结点
+		for now != m.root {
+			if _, found := now.next(runes[pos]); found {
+				break
 			}
+			now = now.fail
 		}
 
 		// 若找到匹配成功的字符串结点, 则指向那个结点, 否则指向根结点
-		if next, ok := now.children[runes[pos]]; ok {
+		if next, ok := now.next(runes[pos]); ok {
 			now = next
 		} else {
 			now = m.root
@@ -165,45 +305,34 @@ func (m *AcModel) FindAll(text string) []string {
 		temp = now
 
 		for temp != m.root {
-			if temp.word != nil {
-				matches = append(matches, *temp.word)
+			if temp.entry != nil && !fn(pos-temp.depth+1, pos+1, temp.entry, ExactConfidence) {
+				return false
 			}
 			temp = temp.fail
 		}
 	}
 
-	var res []string
-	set := make(map[string]struct{})
-
-	for _, word := range matches {
-		if _, ok := set[word]; !ok {
-			set[word] = struct{}{}
-			res = append(res, word)
-		}
-	}
-
-	return res
+	return true
 }
 
-func (m *AcModel) FindAllCount(text string) map[string]int {
-	res := make(map[string]int)
-	var found bool
+// scanBytes 同 scanAutomaton, 直接解码 UTF-8 字节而不转换为 []rune, start 与 end 为字节下标
+func (m *AcModel) scanBytes(text []byte, fn scanFunc) {
 	var temp *acNode
 
 	now := m.root
-	runes := []rune(text)
 
-	for pos := 0; pos < len(runes); pos++ {
-		_, found = now.children[runes[pos]]
-		if !found && now != m.root {
-			now = now.fail
-			for ; !found && now != m.root; now, found = now.children[runes[pos]] {
-				now = now.fail
+	for pos := 0; pos < len(text); {
+		r, size := utf8.DecodeRune(text[pos:])
+		pos += size
+
+		for now != m.root {
+			if _, found := now.next(r); found {
+				break
 			}
+			now = now.fail
 		}
 
-		// 若找到匹配成功的字符串结点, 则指向那个结点, 否则指向根结点
-		if next, ok := now.children[runes[pos]]; ok {
+		if next, ok := now.next(r); ok {
 			now = next
 		} else {
 			now = m.root
@@ -212,127 +341,125 @@ func (m *AcModel) FindAllCount(text string) map[string]int {
 		temp = now
 
 		for temp != m.root {
-			if temp.word != nil {
-				res[*temp.word]++
+			if entry := temp.leaf(); entry != nil {
+				// 结点深度为文字数量, 从结束位置向前解码得到起始的字节下标
+				start := pos
+				for i := 0; i < temp.depth; i++ {
+					_, n := utf8.DecodeLastRune(text[:start])
+					start -= n
+				}
+
+				if (!entry.Stem || isByteBoundary(text, start, pos)) && !fn(start, pos, entry, ExactConfidence) {
+					return
+				}
 			}
 			temp = temp.fail
 		}
 	}
+}
+
+// Stats 返回字典树的结点数量与占用内存的估计值
+func (m *AcModel) Stats() Stats {
+	var stats Stats
+	m.root.stats(&stats)
 
-	return res
+	return stats
 }
 
-func (m *AcModel) FindOne(text string) string {
-	var found bool
-	var temp *acNode
+func (m *AcModel) FindAll(text string, categories ...string) []string {
+	return findAll(m, m.normalizers, text, categories)
+}
 
-	now := m.root
-	runes := []rune(text)
+func (m *AcModel) FindAllCount(text string, categories ...string) map[string]int {
+	return findAllCount(m, m.normalizers, text, categories)
+}
 
-	for pos := 0; pos < len(runes); pos++ {
-		_, found = now.children[runes[pos]]
-		if !found && now != m.root {
-			now = now.fail
-			for ; !found && now != m.root; now, found = now.children[runes[pos]] {
-				now = now.fail
-			}
-		}
+func (m *AcModel) FindOne(text string, categories ...string) string {
+	return findOne(m, m.normalizers, text, categories)
+}
 
-		// 若找到匹配成功的字符串结点, 则指向那个结点, 否则指向根结点
-		if next, ok := now.children[runes[pos]]; ok {
-			now = next
-		} else {
-			now = m.root
-		}
+func (m *AcModel) IsSensitive(text string, categories ...string) bool {
+	return m.FindOne(text, categories...) != ""
+}
 
-		temp = now
+func (m *AcModel) Replace(text string, repl rune, categories ...string) string {
+	return mask(m, m.normalizers, text, NewRuneMasker(repl), categories)
+}
 
-		for temp != m.root {
-			if temp.word != nil {
-				return *temp.word
-			}
-			temp = temp.fail
-		}
-	}
+func (m *AcModel) ReplaceWith(text string, repl rune, categories ...string) string {
+	return mask(m, m.normalizers, text, NewReplacementMasker(NewRuneMasker(repl)), categories)
+}
 
-	return ""
+func (m *AcModel) Mask(text string, masker Masker, categories ...string) string {
+	return mask(m, m.normalizers, text, masker, categories)
 }
 
-func (m *AcModel) IsSensitive(text string) bool {
-	return m.FindOne(text) != ""
+func (m *AcModel) Annotate(text string, format AnnotateFormat, categories ...string) string {
+	return annotate(m, m.normalizers, text, format, categories)
 }
 
-func (m *AcModel) Replace(text string, repl rune) string {
-	var found bool
-	var temp *acNode
+func (m *AcModel) Remove(text string, categories ...string) string {
+	return mask(m, m.normalizers, text, NewFixedMasker(""), categories)
+}
 
-	now := m.root
-	runes := []rune(text)
+func (m *AcModel) FindMatches(text string, categories ...string) []Match {
+	return findMatches(m, m.normalizers, text, categories)
+}
 
-	for pos := 0; pos < len(runes); pos++ {
-		_, found = now.children[runes[pos]]
-		if !found && now != m.root {
-			now = now.fail
-			for ; !found && now != m.root; now, found = now.children[runes[pos]] {
-				now = now.fail
-			}
-		}
+func (m *AcModel) Explain(text string, categories ...string) []Explanation {
+	return explain(m, m.normalizers, text, categories)
+}
 
-		// 若找到匹配成功的字符串结点, 则指向那个结点, 否则指向根结点
-		if next, ok := now.children[runes[pos]]; ok {
-			now = next
-		} else {
-			now = m.root
-		}
+func (m *AcModel) ReplaceEdits(text string, repl rune, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, NewRuneMasker(repl), categories)
+}
 
-		temp = now
+func (m *AcModel) MaskEdits(text string, masker Masker, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, masker, categories)
+}
 
-		for temp != m.root {
-			if temp.word != nil {
-				for i := pos - len([]rune(*temp.word)) + 1; i <= pos; i++ {
-					runes[i] = repl
-				}
-			}
-			temp = temp.fail
-		}
-	}
+func (m *AcModel) RemoveEdits(text string, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, NewFixedMasker(""), categories)
+}
 
-	return string(runes)
+func (m *AcModel) ScanReader(reader io.Reader, fn func(Match), categories ...string) error {
+	return scanReader(m, m.normalizers, m.tailLen(), reader, fn, categories)
 }
 
-func (m *AcModel) Remove(text string) string {
-	var found bool
-	var temp *acNode
+func (m *AcModel) NewReplacingWriter(w io.Writer, repl rune, categories ...string) *ReplacingWriter {
+	return newReplacingWriter(m, m.normalizers, m.tailLen(), w, repl, categories)
+}
 
-	now := m.root
-	runes := []rune(text)
+func (m *AcModel) tailLen() int {
+	return streamTail(m.longest, m.maxGap)
+}
 
-	for pos := 0; pos < len(runes); pos++ {
-		_, found = now.children[runes[pos]]
-		if !found && now != m.root {
-			now = now.fail
-			for ; !found && now != m.root; now, found = now.children[runes[pos]] {
-				now = now.fail
-			}
-		}
+func (m *AcModel) FindAllBytes(text []byte, categories ...string) []string {
+	if !scanBytesSupported(m.normalizers, m.maxGap, m.phonetic, text) {
+		return m.FindAll(string(text), categories...)
+	}
 
-		// 若找到匹配成功的字符串结点, 则指向那个结点, 否则指向根结点
-		if next, ok := now.children[runes[pos]]; ok {
-			now = next
-		} else {
-			now = m.root
-		}
+	var words byteWords
+	m.scanBytes(text, func(_, _ int, entry *dict.Entry, _ float64) bool {
+		words.add(entry, categories)
+		return true
+	})
 
-		temp = now
+	return words.res
+}
 
-		for temp != m.root {
-			if temp.word != nil {
-				runes = append(runes[:pos-len([]rune(*temp.word))+1], runes[pos+1:]...)
-				pos -= len([]rune(*temp.word))
-			}
-			temp = temp.fail
-		}
+func (m *AcModel) ReplaceBytes(text []byte, repl rune, categories ...string) []byte {
+	if !scanBytesSupported(m.normalizers, m.maxGap, m.phonetic, text) {
+		return []byte(m.Replace(string(text), repl, categories...))
 	}
 
-	return string(runes)
+	var spans []Span
+	m.scanBytes(text, func(start, end int, entry *dict.Entry, _ float64) bool {
+		if entry.HasCategory(categories...) {
+			spans = append(spans, Span{Start: start, End: end})
+		}
+		return true
+	})
+
+	return replaceBytes(text, spans, repl)
 }
@@ -2,7 +2,11 @@ package filter
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/sgoware/go-sensitive/dict"
 )
 
 var (
@@ -224,3 +228,62 @@ func Test_Remove(t *testing.T) {
 		})
 	}
 }
+
+func Test_AcAddDelWord(t *testing.T) {
+	filter := NewAcModel()
+
+	filter.AddWords("ab")
+	filter.AddWord("abc")
+	if res := filter.FindAll("abcx"); !reflect.DeepEqual(res, []string{"ab", "abc"}) {
+		t.Errorf("FindAll() after AddWord = %v", res)
+	}
+
+	filter.AddWord("bcx")
+	if res := filter.FindAll("abcx"); !reflect.DeepEqual(res, []string{"ab", "abc", "bcx"}) {
+		t.Errorf("FindAll() after AddWord = %v", res)
+	}
+
+	filter.DelWord("abc")
+	if res := filter.FindAll("abcx"); !reflect.DeepEqual(res, []string{"ab", "bcx"}) {
+		t.Errorf("FindAll() after DelWord = %v", res)
+	}
+}
+
+func Test_AcListen(t *testing.T) {
+	filter := NewAcModel()
+	filter.AddWords(words1...)
+
+	addChan := make(chan dict.Entry)
+	delChan := make(chan string)
+
+	// 同一个词先增后删与先删后增, 修改需要按发送的顺序生效
+	go func() {
+		for i := 0; i < 100; i++ {
+			addChan <- dict.Entry{Word: "新词" + strconv.Itoa(i)}
+		}
+		delChan <- "新词5"
+		delChan <- "敏感词1"
+		addChan <- dict.Entry{Word: "敏感词1", Categories: []string{"a"}}
+		close(addChan)
+		close(delChan)
+	}()
+
+	var batches int
+	listen(addChan, delChan, time.Hour, func(entries []dict.Entry) {
+		batches++
+		filter.AddEntries(entries...)
+	}, func(words []string) {
+		batches++
+		filter.DelWords(words...)
+	})
+
+	if batches != 3 {
+		t.Errorf("listen() made %d batches, want 3", batches)
+	}
+	if res := filter.FindAll("新词5 新词42 敏感词1", "a"); !reflect.DeepEqual(res, []string{"敏感词1"}) {
+		t.Errorf("FindAll() = %v", res)
+	}
+	if res := filter.FindAll("新词5 新词42"); !reflect.DeepEqual(res, []string{"新词4", "新词42"}) {
+		t.Errorf("FindAll() = %v", res)
+	}
+}
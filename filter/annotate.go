@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	AnsiRed     = "\x1b[31m"
+	AnsiYellow  = "\x1b[33m"
+	AnsiMagenta = "\x1b[35m"
+	ansiReset   = "\x1b[0m"
+)
+
+// AnnotateFormat 标注格式, 相互重叠的敏感词合并为一处标注
+type AnnotateFormat interface {
+	// Escape 转义原文中的文本
+	Escape(text string) string
+	// Wrap 包裹匹配到的文本, text 已经过转义, matches 为合并在这一处的所有敏感词
+	Wrap(text string, matches []Match) string
+}
+
+// HtmlFormat 使用 <mark data-word="敏感词" data-cat="分类">...</mark> 标注, 并对原文进行 HTML 转义
+type HtmlFormat struct{}
+
+func NewHtmlFormat() *HtmlFormat {
+	return &HtmlFormat{}
+}
+
+func (f *HtmlFormat) Escape(text string) string {
+	return html.EscapeString(text)
+}
+
+func (f *HtmlFormat) Wrap(text string, matches []Match) string {
+	words, categories := annotateLabels(matches)
+
+	return fmt.Sprintf(`<mark data-word="%s" data-cat="%s">%s</mark>`,
+		html.EscapeString(strings.Join(words, ",")),
+		html.EscapeString(strings.Join(categories, ",")),
+		text,
+	)
+}
+
+// AnsiFormat 使用 ANSI 颜色标注, 用于命令行输出
+// 按分类选择颜色, 使用第一个配置了颜色的分类, 都没有配置时使用 AnsiRed
+type AnsiFormat struct {
+	colors map[string]string
+}
+
+func NewAnsiFormat(colors map[string]string) *AnsiFormat {
+	return &AnsiFormat{
+		colors: colors,
+	}
+}
+
+// Escape 去掉原文中的 ESC 字符, 避免原文中的控制序列影响终端显示
+func (f *AnsiFormat) Escape(text string) string {
+	return strings.ReplaceAll(text, "\x1b", "")
+}
+
+func (f *AnsiFormat) Wrap(text string, matches []Match) string {
+	color := AnsiRed
+
+	_, categories := annotateLabels(matches)
+	for _, category := range categories {
+		if c, ok := f.colors[category]; ok {
+			color = c
+			break
+		}
+	}
+
+	return color + text + ansiReset
+}
+
+// annotateLabels 返回去重后的敏感词与分类
+func annotateLabels(matches []Match) ([]string, []string) {
+	var words, categories []string
+	wordSet := make(map[string]struct{})
+	categorySet := make(map[string]struct{})
+
+	for _, match := range matches {
+		if _, ok := wordSet[match.Word]; !ok {
+			wordSet[match.Word] = struct{}{}
+			words = append(words, match.Word)
+		}
+
+		for _, category := range match.Categories {
+			if _, ok := categorySet[category]; !ok {
+				categorySet[category] = struct{}{}
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	return words, categories
+}
+
+func annotate(s scanner, n normalizers, text string, format AnnotateFormat, categories []string) string {
+	var builder strings.Builder
+
+	runes := []rune(text)
+	cursor := 0
+
+	for _, group := range overlaps(matches(exactScanner{s}, n, runes, categories)) {
+		start, end := group[0].Start, group[0].End
+		for _, match := range group[1:] {
+			if match.End > end {
+				end = match.End
+			}
+		}
+
+		builder.WriteString(format.Escape(string(runes[cursor:start])))
+		builder.WriteString(format.Wrap(format.Escape(string(runes[start:end])), group))
+		cursor = end
+	}
+
+	builder.WriteString(format.Escape(string(runes[cursor:])))
+
+	return builder.String()
+}
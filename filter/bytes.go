@@ -0,0 +1,92 @@
+package filter
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+// scanBytesSupported 是否可以直接在 UTF-8 字节中查找敏感词
+// 规范化, 间隔匹配与语音匹配需要完整的文字序列, 无效的 UTF-8 编码在 rune 接口中会变为 utf8.RuneError, 这些情况使用 rune 接口以保证结果相同
+func scanBytesSupported(n normalizers, maxGap int, phonetic *phoneticIndex, text []byte) bool {
+	return len(n) == 0 && maxGap == 0 && phonetic == nil && utf8.Valid(text)
+}
+
+// isByteBoundary 同 isWordBoundary, start 与 end 为字节下标
+func isByteBoundary(text []byte, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRune(text[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRune(text[end:]); isWordRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// byteWords 按出现顺序收集不重复的敏感词, 没有敏感词时不分配内存
+type byteWords struct {
+	res []string
+	set map[string]struct{}
+}
+
+func (w *byteWords) add(entry *dict.Entry, categories []string) {
+	if !entry.HasCategory(categories...) {
+		return
+	}
+
+	if w.set == nil {
+		w.set = make(map[string]struct{})
+	}
+	if _, ok := w.set[entry.Word]; !ok {
+		w.set[entry.Word] = struct{}{}
+		w.res = append(w.res, entry.Word)
+	}
+}
+
+// replaceBytes 将 spans 覆盖的每个文字替换为 repl, 与 Replace 一样相互重叠的敏感词只替换一次, 没有敏感词时返回 text 本身
+func replaceBytes(text []byte, spans []Span, repl rune) []byte {
+	if len(spans) == 0 {
+		return text
+	}
+
+	res := make([]byte, 0, len(text))
+	cursor := 0
+
+	for _, group := range byteClusters(spans) {
+		res = append(res, text[cursor:group.Start]...)
+		for range string(text[group.Start:group.End]) {
+			res = utf8.AppendRune(res, repl)
+		}
+		cursor = group.End
+	}
+
+	return append(res, text[cursor:]...)
+}
+
+// byteClusters 将相互重叠的区间合并, 返回按起始位置升序排列的区间
+func byteClusters(spans []Span) []Span {
+	var res []Span
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+
+	for _, span := range spans {
+		if last := len(res) - 1; last >= 0 && span.Start < res[last].End {
+			if span.End > res[last].End {
+				res[last].End = span.End
+			}
+			continue
+		}
+
+		res = append(res, span)
+	}
+
+	return res
+}
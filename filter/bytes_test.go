@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_Bytes(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "敏感词1", Categories: []string{"a"}}, {Word: "敏感词2"}, {Word: "感词"}, {Word: "kill", Stem: true},
+	}
+
+	filters := map[string]func() testFilter{
+		"dfa":            func() testFilter { return NewDfaModel() },
+		"ac":             func() testFilter { return NewAcModel() },
+		"dfa normalizer": func() testFilter { return NewDfaModel(NewDiacriticNormalizer()) },
+		"ac normalizer":  func() testFilter { return NewAcModel(NewDiacriticNormalizer()) },
+	}
+
+	texts := []string{
+		"",
+		"没有敏感词",
+		"这是敏感词1, 也是敏感词2",
+		"敏感词1敏感词2敏感词1",
+		"he kills, skills and killing",
+		"kíll 敏感词2",
+		"\xe6\x95敏感词1\xff",
+	}
+
+	for name, newFilter := range filters {
+		filter := newFilter()
+		filter.AddEntries(entries...)
+
+		for _, text := range texts {
+			for _, categories := range [][]string{nil, {"a"}} {
+				if got, want := filter.FindAllBytes([]byte(text), categories...), filter.FindAll(text, categories...); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: FindAllBytes(%q, %v) = %v, want %v", name, text, categories, got, want)
+				}
+				if got, want := string(filter.ReplaceBytes([]byte(text), '*', categories...)), filter.Replace(text, '*', categories...); got != want {
+					t.Errorf("%s: ReplaceBytes(%q, %v) = %q, want %q", name, text, categories, got, want)
+				}
+			}
+		}
+	}
+}
+
+func Test_BytesAllocs(t *testing.T) {
+	text := []byte("这是一段没有敏感词的文本, 但是包含敏感两个字")
+
+	for name, filter := range map[string]testFilter{"dfa": NewDfaModel(), "ac": NewAcModel()} {
+		filter.AddEntries(dict.Entry{Word: "敏感词1"}, dict.Entry{Word: "kill", Stem: true})
+
+		if n := testing.AllocsPerRun(100, func() { filter.FindAllBytes(text) }); n != 0 {
+			t.Errorf("%s: FindAllBytes() allocs = %v, want 0", name, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { filter.ReplaceBytes(text, '*') }); n != 0 {
+			t.Errorf("%s: ReplaceBytes() allocs = %v, want 0", name, n)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package filter
+
+import "unicode"
+
+// CaseNormalizer 大小写规范化器
+// 将文字转为小写, 与 DiacriticNormalizer 一起使用时 "Scheiße" 与 "scheisse" 可以相互匹配
+type CaseNormalizer struct{}
+
+func NewCaseNormalizer() *CaseNormalizer {
+	return &CaseNormalizer{}
+}
+
+func (n *CaseNormalizer) Name() string {
+	return "case"
+}
+
+func (n *CaseNormalizer) Normalize(runes []rune) ([]rune, []Span) {
+	res := make([]rune, len(runes))
+	spans := make([]Span, len(runes))
+
+	for i, r := range runes {
+		res[i] = unicode.ToLower(r)
+		spans[i] = Span{Start: i, End: i + 1}
+	}
+
+	return res, spans
+}
@@ -0,0 +1,175 @@
+package filter
+
+import "unsafe"
+
+const (
+	denseMinChildren = 32 // 子结点不少于这个数量时才考虑使用稠密表
+	denseMaxRatio    = 8  // 稠密表的长度最多为子结点数量的倍数
+)
+
+// nodeChildren 字典树结点的子结点, 零值可以直接使用
+// 子结点较少时使用按文字排序的数组二分查找, 子结点多且文字集中时(如根结点)改为以最小文字为起点的稠密表直接下标访问
+type nodeChildren[N comparable] struct {
+	keys  []rune // 排序数组中的文字, 使用稠密表时为 nil
+	nodes []N    // 排序数组中与 keys 对应的结点, 或稠密表中文字 low+i 对应的结点
+	low   rune
+	count int // 稠密表中子结点的数量
+	dense bool
+}
+
+func (c *nodeChildren[N]) len() int {
+	if c.dense {
+		return c.count
+	}
+
+	return len(c.keys)
+}
+
+func (c *nodeChildren[N]) get(r rune) (N, bool) {
+	var zero N
+
+	if c.dense {
+		i := int(r) - int(c.low)
+		if i < 0 || i >= len(c.nodes) {
+			return zero, false
+		}
+		return c.nodes[i], c.nodes[i] != zero
+	}
+
+	if i := c.search(r); i < len(c.keys) && c.keys[i] == r {
+		return c.nodes[i], true
+	}
+
+	return zero, false
+}
+
+// set 加入或替换文字 r 对应的子结点
+func (c *nodeChildren[N]) set(r rune, node N) {
+	var zero N
+
+	if c.dense {
+		if i := int(r) - int(c.low); i >= 0 && i < len(c.nodes) {
+			if c.nodes[i] == zero {
+				c.count++
+			}
+			c.nodes[i] = node
+			return
+		}
+		c.toSorted()
+	}
+
+	i := c.search(r)
+	if i < len(c.keys) && c.keys[i] == r {
+		c.nodes[i] = node
+		return
+	}
+
+	c.keys = append(c.keys, 0)
+	copy(c.keys[i+1:], c.keys[i:])
+	c.keys[i] = r
+
+	c.nodes = append(c.nodes, zero)
+	copy(c.nodes[i+1:], c.nodes[i:])
+	c.nodes[i] = node
+
+	c.adapt()
+}
+
+// del 删除文字 r 对应的子结点
+func (c *nodeChildren[N]) del(r rune) {
+	var zero N
+
+	if c.dense {
+		if i := int(r) - int(c.low); i >= 0 && i < len(c.nodes) && c.nodes[i] != zero {
+			c.nodes[i] = zero
+			c.count--
+			c.adapt()
+		}
+		return
+	}
+
+	if i := c.search(r); i < len(c.keys) && c.keys[i] == r {
+		c.keys = append(c.keys[:i], c.keys[i+1:]...)
+
+		// 清空末尾的结点, 使被删除的结点可以被回收
+		copy(c.nodes[i:], c.nodes[i+1:])
+		c.nodes[len(c.nodes)-1] = zero
+		c.nodes = c.nodes[:len(c.nodes)-1]
+	}
+}
+
+// each 按文字升序遍历子结点
+func (c *nodeChildren[N]) each(fn func(r rune, node N)) {
+	var zero N
+
+	if c.dense {
+		for i, node := range c.nodes {
+			if node != zero {
+				fn(c.low+rune(i), node)
+			}
+		}
+		return
+	}
+
+	for i, r := range c.keys {
+		fn(r, c.nodes[i])
+	}
+}
+
+// bytes 子结点集合中数组占用内存的估计值, 不包括集合本身与子结点
+func (c *nodeChildren[N]) bytes() int {
+	var zero N
+
+	return cap(c.keys)*int(unsafe.Sizeof(rune(0))) + cap(c.nodes)*int(unsafe.Sizeof(zero))
+}
+
+// search 返回第一个不小于 r 的文字在排序数组中的下标
+func (c *nodeChildren[N]) search(r rune) int {
+	low, high := 0, len(c.keys)
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if c.keys[mid] < r {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	return low
+}
+
+// adapt 根据子结点的数量与文字的分布切换排序数组与稠密表
+func (c *nodeChildren[N]) adapt() {
+	if c.dense {
+		if c.count < denseMinChildren/2 || len(c.nodes) > 2*denseMaxRatio*c.count {
+			c.toSorted()
+		}
+		return
+	}
+
+	if n := len(c.keys); n >= denseMinChildren && int(c.keys[n-1])-int(c.keys[0])+1 <= denseMaxRatio*n {
+		c.toDense()
+	}
+}
+
+func (c *nodeChildren[N]) toDense() {
+	low := c.keys[0]
+	nodes := make([]N, int(c.keys[len(c.keys)-1])-int(low)+1)
+	for i, r := range c.keys {
+		nodes[r-low] = c.nodes[i]
+	}
+
+	c.count = len(c.keys)
+	c.keys, c.nodes, c.low, c.dense = nil, nodes, low, true
+}
+
+func (c *nodeChildren[N]) toSorted() {
+	keys := make([]rune, 0, c.count)
+	nodes := make([]N, 0, c.count)
+	c.each(func(r rune, node N) {
+		keys = append(keys, r)
+		nodes = append(nodes, node)
+	})
+
+	c.keys, c.nodes, c.low, c.count, c.dense = keys, nodes, 0, 0, false
+}
@@ -0,0 +1,103 @@
+package filter
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_NodeChildren(t *testing.T) {
+	var children nodeChildren[*dfaNode]
+	nodes := make(map[rune]*dfaNode)
+
+	check := func(step string) {
+		if children.len() != len(nodes) {
+			t.Fatalf("%s: len() = %v, want %v", step, children.len(), len(nodes))
+		}
+
+		var last rune = -1
+		children.each(func(r rune, node *dfaNode) {
+			if r <= last {
+				t.Fatalf("%s: each() not sorted at %q", step, r)
+			}
+			last = r
+
+			if nodes[r] != node {
+				t.Fatalf("%s: each() %q = %p, want %p", step, r, node, nodes[r])
+			}
+		})
+
+		for r := rune('一') - 10; r < '一'+100; r++ {
+			node, ok := children.get(r)
+			if want, exist := nodes[r]; ok != exist || node != want {
+				t.Fatalf("%s: get(%q) = %p, %v, want %p, %v", step, r, node, ok, want, exist)
+			}
+		}
+	}
+
+	// 子结点较少时使用排序数组
+	for r := rune('一'); r < '一'+40; r += 4 {
+		nodes[r] = newDfaNode()
+		children.set(r, nodes[r])
+	}
+	check("sparse")
+	if children.dense {
+		t.Errorf("sparse children should use sorted array")
+	}
+
+	// 子结点多且集中时使用稠密表
+	for r := rune('一'); r < '一'+64; r++ {
+		nodes[r] = newDfaNode()
+		children.set(r, nodes[r])
+	}
+	check("dense")
+	if !children.dense {
+		t.Errorf("dense children should use dense table")
+	}
+
+	// 加入范围外的子结点
+	nodes['一'+90] = newDfaNode()
+	children.set('一'+90, nodes['一'+90])
+	check("extend")
+
+	// 删除后改回排序数组
+	for r := rune('一'); r < '一'+60; r++ {
+		delete(nodes, r)
+		children.del(r)
+	}
+	check("delete")
+	if children.dense {
+		t.Errorf("children should use sorted array after delete")
+	}
+}
+
+func Test_Stats(t *testing.T) {
+	data, err := os.ReadFile("../dict/default_dict.txt")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	words := strings.Fields(string(data))
+
+	dfa := NewDfaModel()
+	dfa.AddWords(words...)
+
+	dat := NewFilterDat()
+	dat.AddWords(words...)
+
+	dfaStats, datStats := dfa.Stats(), dat.Stats()
+	t.Logf("default_dict.txt: %d words, dfa %+v, dat %+v", len(words), dfaStats, datStats)
+
+	if dfaStats.Nodes != datStats.Nodes {
+		t.Errorf("Stats().Nodes = %v, want %v", datStats.Nodes, dfaStats.Nodes)
+	}
+	if dfaStats.DenseNodes == 0 {
+		t.Errorf("Stats().DenseNodes = 0, root should use dense table")
+	}
+
+	dfa.DelWords(words...)
+	if res := dfa.Stats(); !reflect.DeepEqual(res, Stats{Nodes: 1, Bytes: res.Bytes}) {
+		t.Errorf("Stats() after delete = %+v, want only root", res)
+	}
+}
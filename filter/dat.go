@@ -0,0 +1,861 @@
+package filter
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+const (
+	datRoot     = 0
+	datFree     = -1 // check 中表示空闲的位置
+	datNoParent = -2 // 根结点没有父结点
+)
+
+// datTrie 双数组字典树, 结点 s 经过编码为 c 的文字转移到 base[s]+c, 且 check[base[s]+c] == s
+// 文字先映射为从 1 开始的连续编码, 使数组尽量紧凑, fail 为 AC 自动机的失败指针
+// 构建完成后只读, hidden 为之后被删除或覆盖的结点, 查找时忽略这些结点上的敏感词
+type datTrie struct {
+	codes   map[rune]int32
+	base    []int32
+	check   []int32
+	fail    []int32
+	depth   []int32
+	entries []*dict.Entry
+	hidden  map[int32]struct{}
+
+	maxGap   int
+	spans    spanLimit
+	longest  int
+	phonetic *phoneticIndex
+}
+
+// child 结点 s 经过编码 c 转移到的结点
+func (t *datTrie) child(s, c int32) (int32, bool) {
+	next := t.base[s] + c
+	if next < 0 || int(next) >= len(t.check) || t.check[next] != s {
+		return 0, false
+	}
+
+	return next, true
+}
+
+// datNode 双数组中的结点, 用于在双数组中查找有间隔的敏感词
+type datNode struct {
+	trie  *datTrie
+	index int32
+}
+
+func (n datNode) next(r rune) (datNode, bool) {
+	code, ok := n.trie.codes[r]
+	if !ok {
+		return datNode{}, false
+	}
+
+	next, ok := n.trie.child(n.index, code)
+	return datNode{trie: n.trie, index: next}, ok
+}
+
+func (n datNode) leaf() *dict.Entry {
+	return n.trie.entry(n.index)
+}
+
+// datUnion 同时在完整的双数组与新加入的敏感词构建的双数组中前进的结点, 查找有间隔的敏感词的顺序与由所有敏感词构建的一个双数组相同
+// 已经无法前进的一方 trie 为 nil
+type datUnion struct {
+	base, overlay datNode
+}
+
+func (n datUnion) next(r rune) (datUnion, bool) {
+	var res datUnion
+
+	if n.base.trie != nil {
+		if next, ok := n.base.next(r); ok {
+			res.base = next
+		}
+	}
+	if n.overlay.trie != nil {
+		if next, ok := n.overlay.next(r); ok {
+			res.overlay = next
+		}
+	}
+
+	return res, res.base.trie != nil || res.overlay.trie != nil
+}
+
+func (n datUnion) leaf() *dict.Entry {
+	if n.overlay.trie != nil {
+		if entry := n.overlay.leaf(); entry != nil {
+			return entry
+		}
+	}
+	if n.base.trie != nil {
+		return n.base.leaf()
+	}
+
+	return nil
+}
+
+// entry 结点 s 上的敏感词, 被隐藏的结点返回 nil
+func (t *datTrie) entry(s int32) *dict.Entry {
+	if len(t.hidden) > 0 {
+		if _, ok := t.hidden[s]; ok {
+			return nil
+		}
+	}
+
+	return t.entries[s]
+}
+
+// lookup 从根结点沿 runes 找到的结点
+func (t *datTrie) lookup(runes []rune) (int32, bool) {
+	now := int32(datRoot)
+
+	for _, r := range runes {
+		code, ok := t.codes[r]
+		if !ok {
+			return 0, false
+		}
+		if now, ok = t.child(now, code); !ok {
+			return 0, false
+		}
+	}
+
+	return now, true
+}
+
+// step 自动机从结点 now 读入文字 r 后到达的结点
+func (t *datTrie) step(now int32, r rune) int32 {
+	code, ok := t.codes[r]
+	if !ok {
+		return datRoot
+	}
+
+	return t.transition(now, code)
+}
+
+// scanAutomata 同时使用多个自动机查找敏感词, 每个位置上按长度从长到短返回所有自动机匹配到的敏感词, 与由所有敏感词构建的一个自动机的结果顺序相同
+func scanAutomata(tries []*datTrie, runes []rune, fn scanFunc) bool {
+	states := make([]int32, len(tries))
+	chains := make([]int32, len(tries))
+
+	for pos, r := range runes {
+		for i, t := range tries {
+			states[i] = t.step(states[i], r)
+			chains[i] = states[i]
+		}
+
+		for {
+			best := -1
+			for i, t := range tries {
+				for chains[i] != datRoot && t.entry(chains[i]) == nil {
+					chains[i] = t.fail[chains[i]]
+				}
+				if chains[i] != datRoot && (best < 0 || t.depth[chains[i]] > tries[best].depth[chains[best]]) {
+					best = i
+				}
+			}
+			if best < 0 {
+				break
+			}
+
+			t, now := tries[best], chains[best]
+			if !fn(pos-int(t.depth[now])+1, pos+1, t.entries[now], ExactConfidence) {
+				return false
+			}
+			chains[best] = t.fail[now]
+		}
+	}
+
+	return true
+}
+
+// transition 沿失败指针回退, 直到找到可以接受编码 c 的结点或回到根结点
+func (t *datTrie) transition(now, c int32) int32 {
+	for {
+		if next, ok := t.child(now, c); ok {
+			return next
+		}
+		if now == datRoot {
+			return datRoot
+		}
+		now = t.fail[now]
+	}
+}
+
+// scanBytes 同 scanAutomaton, 直接解码 UTF-8 字节而不转换为 []rune, start 与 end 为字节下标
+func (t *datTrie) scanBytes(text []byte, fn scanFunc) {
+	now := int32(datRoot)
+
+	for pos := 0; pos < len(text); {
+		r, size := utf8.DecodeRune(text[pos:])
+		pos += size
+
+		code, ok := t.codes[r]
+		if !ok {
+			now = datRoot
+			continue
+		}
+
+		now = t.transition(now, code)
+
+		for temp := now; temp != datRoot; temp = t.fail[temp] {
+			entry := t.entry(temp)
+			if entry == nil {
+				continue
+			}
+
+			start := pos
+			for i := int32(0); i < t.depth[temp]; i++ {
+				_, n := utf8.DecodeLastRune(text[:start])
+				start -= n
+			}
+
+			if (!entry.Stem || isByteBoundary(text, start, pos)) && !fn(start, pos, entry, ExactConfidence) {
+				return
+			}
+		}
+	}
+}
+
+// datBuilder 构建双数组时使用的临时字典树
+type datBuilder struct {
+	children map[rune]*datBuilder
+	entry    *dict.Entry
+}
+
+func newDatBuilder() *datBuilder {
+	return &datBuilder{
+		children: make(map[rune]*datBuilder),
+	}
+}
+
+// insert 同 DfaModel.insert, 词形扩展不会覆盖字典中的同名敏感词, runes 为规范化后的 word
+func (b *datBuilder) insert(runes []rune, word string, entry *dict.Entry) {
+	now := b
+	for _, r := range runes {
+		next, ok := now.children[r]
+		if !ok {
+			next = newDatBuilder()
+			now.children[r] = next
+		}
+		now = next
+	}
+
+	if leaf := now.entry; leaf != nil && leaf.Word == word && entry.Word != word {
+		return
+	}
+
+	now.entry = entry
+}
+
+// datWork 双数组构建过程中的状态
+type datWork struct {
+	*datTrie
+	free int32 // 之前的位置都已被占用
+}
+
+func (w *datWork) grow(size int32) {
+	for int(size) > len(w.check) {
+		w.base = append(w.base, 0)
+		w.check = append(w.check, datFree)
+		w.fail = append(w.fail, datRoot)
+		w.depth = append(w.depth, 0)
+		w.entries = append(w.entries, nil)
+	}
+}
+
+// place 找到使所有子结点的位置都空闲的 base
+func (w *datWork) place(codes []int32) int32 {
+	for pos := w.free; ; pos++ {
+		w.grow(pos + 1)
+		if w.check[pos] != datFree {
+			continue
+		}
+
+		base := pos - codes[0]
+		ok := true
+		for _, c := range codes[1:] {
+			w.grow(base + c + 1)
+			if w.check[base+c] != datFree {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			return base
+		}
+	}
+}
+
+// buildDat 由临时字典树构建双数组与失败指针
+func buildDat(root *datBuilder) *datTrie {
+	w := &datWork{datTrie: &datTrie{codes: make(map[rune]int32)}, free: 1}
+
+	// 按文字排序后编码
+	var runes []rune
+	var collect func(node *datBuilder)
+	collect = func(node *datBuilder) {
+		for r, child := range node.children {
+			if _, ok := w.codes[r]; !ok {
+				w.codes[r] = 0
+				runes = append(runes, r)
+			}
+			collect(child)
+		}
+	}
+	collect(root)
+
+	sort.Slice(runes, func(i, j int) bool {
+		return runes[i] < runes[j]
+	})
+	for i, r := range runes {
+		w.codes[r] = int32(i + 1)
+	}
+
+	w.grow(1)
+	w.check[datRoot] = datNoParent
+
+	type item struct {
+		node   *datBuilder
+		index  int32
+		parent int32
+		code   int32
+	}
+
+	// 按层放置结点, 同时记录层序用于计算失败指针
+	queue := []item{{node: root, index: datRoot, parent: datNoParent}}
+	for i := 0; i < len(queue); i++ {
+		now := queue[i]
+		if len(now.node.children) == 0 {
+			continue
+		}
+
+		codes := make([]int32, 0, len(now.node.children))
+		children := make(map[int32]*datBuilder, len(now.node.children))
+		for r, child := range now.node.children {
+			codes = append(codes, w.codes[r])
+			children[w.codes[r]] = child
+		}
+		sort.Slice(codes, func(i, j int) bool {
+			return codes[i] < codes[j]
+		})
+
+		base := w.place(codes)
+		w.base[now.index] = base
+
+		for _, c := range codes {
+			index := base + c
+			w.check[index] = now.index
+			w.depth[index] = w.depth[now.index] + 1
+			w.entries[index] = children[c].entry
+
+			queue = append(queue, item{node: children[c], index: index, parent: now.index, code: c})
+		}
+
+		for int(w.free) < len(w.check) && w.check[w.free] != datFree {
+			w.free++
+		}
+	}
+
+	for _, now := range queue[1:] {
+		if now.parent == datRoot {
+			w.fail[now.index] = datRoot
+			continue
+		}
+
+		w.fail[now.index] = w.transition(w.fail[now.parent], now.code)
+	}
+
+	// 去掉末尾没有使用的位置
+	size := len(w.check)
+	for size > 1 && w.check[size-1] == datFree {
+		size--
+	}
+	w.base, w.check, w.fail, w.depth, w.entries = w.base[:size], w.check[:size], w.fail[:size], w.depth[:size], w.entries[:size]
+
+	return w.datTrie
+}
+
+// datEntry seq 为加入的顺序, 规范化后相同的敏感词以后加入的为准
+type datEntry struct {
+	entry *dict.Entry
+	seq   uint64
+}
+
+// datForm 敏感词或其词形扩展规范化后的文字
+type datForm struct {
+	word  string
+	runes []rune
+}
+
+const (
+	datOverlayLimit = 1024        // 增量修改的敏感词超过这个数量时立即重新构建双数组
+	datCompactDelay = time.Second // 最后一次修改后等待这么久在后台重新构建双数组
+)
+
+// datSnapshot 查找时使用的一组双数组, tries[0] 为上次完整构建的双数组, 之后加入的敏感词构建为一个小的双数组放在 tries[1]
+// removed 为 tries[0] 中已删除或被替换的敏感词, 用于过滤语音匹配的结果
+type datSnapshot struct {
+	tries   []*datTrie
+	removed map[*dict.Entry]struct{}
+	gap     GapOption
+	longest int
+	maxGap  int
+	maxSpan int
+}
+
+// bytesTrie 没有增量修改时返回完整的双数组, 用于直接在 UTF-8 字节中查找
+func (s *datSnapshot) bytesTrie() (*datTrie, bool) {
+	if len(s.tries) > 1 || len(s.tries[0].hidden) > 0 {
+		return nil, false
+	}
+
+	return s.tries[0], true
+}
+
+// FilterDat 基于双数组字典树的 AC 自动机过滤器, 结点保存在几个连续的数组中, 比使用 map 的结点占用更少的内存
+// 修改敏感词时只为新加入的敏感词构建一个小的双数组, 并隐藏完整双数组中被删除或覆盖的结点, 查找时同时使用两个双数组且不加锁
+// 修改停止 datCompactDelay 后或增量修改过多时再由所有敏感词重新构建完整的双数组
+type FilterDat struct {
+	normalizers normalizers
+
+	mu       sync.Mutex
+	entries  map[string]datEntry
+	seq      uint64
+	gap      GapOption
+	phonetic bool
+	base     *datTrie                 // 上次完整构建的双数组
+	pending  map[string]datEntry      // 之后加入的敏感词
+	removed  map[*dict.Entry]struct{} // 之后从 base 中删除或被替换的敏感词
+	timer    *time.Timer
+
+	trie atomic.Pointer[datSnapshot]
+}
+
+// NewFilterDat 创建双数组字典树过滤器, 敏感词和文本在匹配前会依次经过 normalizers 规范化
+func NewFilterDat(normalizers ...Normalizer) *FilterDat {
+	m := &FilterDat{
+		normalizers: normalizers,
+		entries:     make(map[string]datEntry),
+	}
+	m.rebuild()
+
+	return m
+}
+
+// SetGap 设置所有敏感词相邻文字之间允许的间隔
+func (m *FilterDat) SetGap(option GapOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gap = option
+	m.rebuild()
+}
+
+// SetPhonetic 启用或关闭语音匹配, 见 DfaModel.SetPhonetic
+func (m *FilterDat) SetPhonetic(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.phonetic = enabled
+	m.rebuild()
+}
+
+func (m *FilterDat) AddWords(words ...string) {
+	entries := make([]dict.Entry, 0, len(words))
+	for _, word := range words {
+		entries = append(entries, dict.Entry{Word: word})
+	}
+
+	m.AddEntries(entries...)
+}
+
+func (m *FilterDat) AddWord(word string) {
+	m.AddWords(word)
+}
+
+func (m *FilterDat) AddEntries(entries ...dict.Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range entries {
+		entry := entries[i]
+		m.remove(entry.Word)
+
+		m.seq++
+		m.entries[entry.Word] = datEntry{entry: &entry, seq: m.seq}
+		m.pending[entry.Word] = m.entries[entry.Word]
+	}
+
+	m.update()
+}
+
+func (m *FilterDat) AddEntry(entry dict.Entry) {
+	m.AddEntries(entry)
+}
+
+func (m *FilterDat) DelWords(words ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, word := range words {
+		m.remove(word)
+		delete(m.entries, word)
+	}
+
+	m.update()
+}
+
+func (m *FilterDat) DelWord(word string) {
+	m.DelWords(word)
+}
+
+// remove 记录 word 已被删除或替换, 调用时需持有 mu
+func (m *FilterDat) remove(word string) {
+	old, ok := m.entries[word]
+	if !ok {
+		return
+	}
+
+	if _, ok := m.pending[word]; ok {
+		delete(m.pending, word)
+	} else {
+		m.removed[old.entry] = struct{}{}
+	}
+}
+
+// update 增量修改较少时只重新构建新加入的敏感词, 并在修改停止后在后台重新构建完整的双数组, 调用时需持有 mu
+func (m *FilterDat) update() {
+	if len(m.pending)+len(m.removed) > datOverlayLimit {
+		m.rebuild()
+		return
+	}
+
+	m.publish()
+
+	if m.timer == nil {
+		m.timer = time.AfterFunc(datCompactDelay, m.compact)
+	} else {
+		m.timer.Reset(datCompactDelay)
+	}
+}
+
+// compact 把增量修改合并到完整的双数组中
+func (m *FilterDat) compact() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.pending)+len(m.removed) > 0 {
+		m.rebuild()
+	}
+}
+
+// forms 返回敏感词及其词形扩展规范化后的文字
+func (m *FilterDat) forms(entry *dict.Entry) []datForm {
+	res := make([]datForm, 0, 1)
+
+	for _, word := range append([]string{entry.Word}, inflections(entry)...) {
+		runes, _ := m.normalizers.normalize([]rune(word))
+		res = append(res, datForm{word: word, runes: runes})
+	}
+
+	return res
+}
+
+// build 按加入顺序由 entries 构建双数组, 已在 base 中的结点由 keep 决定是否加入, 调用时需持有 mu
+func (m *FilterDat) build(entries []datEntry, keep func(form datForm, entry *dict.Entry) bool) *datTrie {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+
+	var longest int
+	var spans spanLimit
+	maxGap := m.gap.MaxGap
+
+	builder := newDatBuilder()
+	for _, e := range entries {
+		for _, form := range m.forms(e.entry) {
+			if keep != nil && !keep(form, e.entry) {
+				continue
+			}
+			if len(form.runes) > longest {
+				longest = len(form.runes)
+			}
+			builder.insert(form.runes, form.word, e.entry)
+		}
+
+		if e.entry.MaxGap > maxGap {
+			maxGap = e.entry.MaxGap
+		}
+		spans.add(e.entry)
+	}
+
+	trie := buildDat(builder)
+	trie.maxGap, trie.spans, trie.longest = maxGap, spans, longest
+
+	if m.phonetic {
+		trie.phonetic = newPhoneticIndex()
+		for _, e := range entries {
+			trie.phonetic.add(e.entry)
+		}
+	}
+
+	return trie
+}
+
+// rebuild 由所有敏感词重新构建完整的双数组, 调用时需持有 mu
+func (m *FilterDat) rebuild() {
+	entries := make([]datEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+
+	m.base = m.build(entries, nil)
+	m.pending = make(map[string]datEntry)
+	m.removed = make(map[*dict.Entry]struct{})
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+
+	m.publish()
+}
+
+// publish 由 base 与增量修改得到新的 datSnapshot 并原子地替换, 调用时需持有 mu
+// 新加入的敏感词与 base 中的结点规范化后相同时隐藏 base 中的结点, 与重新构建时以后加入的为准相同, 但词形扩展不会覆盖字典中的同名敏感词
+func (m *FilterDat) publish() {
+	snapshot := &datSnapshot{
+		tries:   []*datTrie{m.base},
+		removed: make(map[*dict.Entry]struct{}, len(m.removed)),
+		gap:     m.gap,
+		longest: m.base.longest,
+		maxGap:  m.base.maxGap,
+		maxSpan: m.base.spans.bound(m.gap),
+	}
+
+	if len(m.pending) == 0 && len(m.removed) == 0 {
+		m.trie.Store(snapshot)
+		return
+	}
+
+	hidden := make(map[int32]struct{})
+	for entry := range m.removed {
+		snapshot.removed[entry] = struct{}{}
+		for _, form := range m.forms(entry) {
+			if s, ok := m.base.lookup(form.runes); ok && m.base.entries[s] == entry {
+				hidden[s] = struct{}{}
+			}
+		}
+	}
+
+	if len(m.pending) > 0 {
+		entries := make([]datEntry, 0, len(m.pending))
+		for _, entry := range m.pending {
+			entries = append(entries, entry)
+		}
+
+		overlay := m.build(entries, func(form datForm, entry *dict.Entry) bool {
+			s, ok := m.base.lookup(form.runes)
+			if !ok || m.base.entries[s] == nil {
+				return true
+			}
+			if _, ok := hidden[s]; !ok && m.base.entries[s].Word == form.word && entry.Word != form.word {
+				return false
+			}
+
+			hidden[s] = struct{}{}
+			return true
+		})
+
+		snapshot.tries = append(snapshot.tries, overlay)
+		if overlay.longest > snapshot.longest {
+			snapshot.longest = overlay.longest
+		}
+		if overlay.maxGap > snapshot.maxGap {
+			snapshot.maxGap = overlay.maxGap
+		}
+
+		spans := m.base.spans
+		spans.merge(overlay.spans)
+		snapshot.maxSpan = spans.bound(m.gap)
+	}
+
+	base := *m.base
+	base.hidden = hidden
+	snapshot.tries[0] = &base
+
+	m.trie.Store(snapshot)
+}
+
+// Listen 监听数据源中敏感词的变化, 每 listenInterval 按读到的顺序批量修改一次, 见 listen
+func (m *FilterDat) Listen(addChan <-chan dict.Entry, delChan <-chan string) {
+	go listen(addChan, delChan, listenInterval, func(entries []dict.Entry) {
+		m.AddEntries(entries...)
+	}, func(words []string) {
+		m.DelWords(words...)
+	})
+}
+
+// scan 同 AcModel.scan, 一次查找只使用同一组双数组, 结果的顺序与由所有敏感词构建的一个双数组相同
+func (m *FilterDat) scan(runes []rune, fn scanFunc) {
+	snapshot := m.trie.Load()
+	fn = bounded(runes, fn)
+
+	if !scanAutomata(snapshot.tries, runes, fn) {
+		return
+	}
+
+	if snapshot.maxGap > 0 {
+		root := datUnion{base: datNode{trie: snapshot.tries[0], index: datRoot}}
+		if len(snapshot.tries) > 1 {
+			root.overlay = datNode{trie: snapshot.tries[1], index: datRoot}
+		}
+
+		if !gapScan(root, runes, snapshot.gap, snapshot.maxGap, snapshot.maxSpan, true, fn) {
+			return
+		}
+	}
+
+	var indexes []*phoneticIndex
+	for _, trie := range snapshot.tries {
+		if trie.phonetic != nil {
+			indexes = append(indexes, trie.phonetic)
+		}
+	}
+	if len(indexes) > 0 {
+		scanPhonetic(indexes, runes, func(start, end int, entry *dict.Entry, confidence float64) bool {
+			if _, ok := snapshot.removed[entry]; ok {
+				return true
+			}
+			return fn(start, end, entry, confidence)
+		})
+	}
+}
+
+// Stats 返回所有双数组中的结点数量与占用内存的估计值, 文字编码表按每个文字一个键值对估计
+func (m *FilterDat) Stats() Stats {
+	var stats Stats
+
+	for _, trie := range m.trie.Load().tries {
+		stats.Bytes += (cap(trie.base)+cap(trie.check)+cap(trie.fail)+cap(trie.depth))*int(unsafe.Sizeof(int32(0))) +
+			cap(trie.entries)*int(unsafe.Sizeof((*dict.Entry)(nil))) +
+			len(trie.codes)*int(unsafe.Sizeof(rune(0))+unsafe.Sizeof(int32(0)))
+
+		for _, parent := range trie.check {
+			if parent != datFree {
+				stats.Nodes++
+			}
+		}
+	}
+
+	return stats
+}
+
+func (m *FilterDat) FindAll(text string, categories ...string) []string {
+	return findAll(m, m.normalizers, text, categories)
+}
+
+func (m *FilterDat) FindAllCount(text string, categories ...string) map[string]int {
+	return findAllCount(m, m.normalizers, text, categories)
+}
+
+func (m *FilterDat) FindOne(text string, categories ...string) string {
+	return findOne(m, m.normalizers, text, categories)
+}
+
+func (m *FilterDat) IsSensitive(text string, categories ...string) bool {
+	return m.FindOne(text, categories...) != ""
+}
+
+func (m *FilterDat) Replace(text string, repl rune, categories ...string) string {
+	return mask(m, m.normalizers, text, NewRuneMasker(repl), categories)
+}
+
+func (m *FilterDat) ReplaceWith(text string, repl rune, categories ...string) string {
+	return mask(m, m.normalizers, text, NewReplacementMasker(NewRuneMasker(repl)), categories)
+}
+
+func (m *FilterDat) Mask(text string, masker Masker, categories ...string) string {
+	return mask(m, m.normalizers, text, masker, categories)
+}
+
+func (m *FilterDat) Annotate(text string, format AnnotateFormat, categories ...string) string {
+	return annotate(m, m.normalizers, text, format, categories)
+}
+
+func (m *FilterDat) Remove(text string, categories ...string) string {
+	return mask(m, m.normalizers, text, NewFixedMasker(""), categories)
+}
+
+func (m *FilterDat) FindMatches(text string, categories ...string) []Match {
+	return findMatches(m, m.normalizers, text, categories)
+}
+
+func (m *FilterDat) Explain(text string, categories ...string) []Explanation {
+	return explain(m, m.normalizers, text, categories)
+}
+
+func (m *FilterDat) ReplaceEdits(text string, repl rune, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, NewRuneMasker(repl), categories)
+}
+
+func (m *FilterDat) MaskEdits(text string, masker Masker, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, masker, categories)
+}
+
+func (m *FilterDat) RemoveEdits(text string, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, NewFixedMasker(""), categories)
+}
+
+func (m *FilterDat) ScanReader(reader io.Reader, fn func(Match), categories ...string) error {
+	return scanReader(m, m.normalizers, m.tailLen(), reader, fn, categories)
+}
+
+func (m *FilterDat) NewReplacingWriter(w io.Writer, repl rune, categories ...string) *ReplacingWriter {
+	return newReplacingWriter(m, m.normalizers, m.tailLen(), w, repl, categories)
+}
+
+func (m *FilterDat) tailLen() int {
+	snapshot := m.trie.Load()
+
+	return streamTail(snapshot.longest, snapshot.maxGap)
+}
+
+func (m *FilterDat) FindAllBytes(text []byte, categories ...string) []string {
+	trie, ok := m.trie.Load().bytesTrie()
+	if !ok || !scanBytesSupported(m.normalizers, trie.maxGap, trie.phonetic, text) {
+		return m.FindAll(string(text), categories...)
+	}
+
+	var words byteWords
+	trie.scanBytes(text, func(_, _ int, entry *dict.Entry, _ float64) bool {
+		words.add(entry, categories)
+		return true
+	})
+
+	return words.res
+}
+
+func (m *FilterDat) ReplaceBytes(text []byte, repl rune, categories ...string) []byte {
+	trie, ok := m.trie.Load().bytesTrie()
+	if !ok || !scanBytesSupported(m.normalizers, trie.maxGap, trie.phonetic, text) {
+		return []byte(m.Replace(string(text), repl, categories...))
+	}
+
+	var spans []Span
+	trie.scanBytes(text, func(start, end int, entry *dict.Entry, _ float64) bool {
+		if entry.HasCategory(categories...) {
+			spans = append(spans, Span{Start: start, End: end})
+		}
+		return true
+	})
+
+	return replaceBytes(text, spans, repl)
+}
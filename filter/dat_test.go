@@ -0,0 +1,198 @@
+package filter
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_FilterDat(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "敏感词1", Categories: []string{"a"}}, {Word: "敏感词2"}, {Word: "感词"}, {Word: "词2"},
+		{Word: "kill", Stem: true}, {Word: "shit"}, {Word: "敏词", MaxGap: 2},
+	}
+
+	texts := []string{
+		"",
+		"没有敏感词",
+		"这是敏感词1, 也是敏感词2",
+		"敏感词1敏感词2敏感词1",
+		"he kills, skills and killing",
+		"敏a感b词2, 敏xx词",
+		"kíll the sheit",
+	}
+
+	tests := []struct {
+		name        string
+		normalizers []Normalizer
+		gap         GapOption
+		phonetic    bool
+	}{
+		{name: "default"},
+		{name: "normalizer", normalizers: []Normalizer{NewDiacriticNormalizer()}},
+		{name: "gap", gap: GapOption{MaxGap: 1}},
+		{name: "phonetic", phonetic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := NewAcModel(tt.normalizers...)
+			ac.SetGap(tt.gap)
+			ac.SetPhonetic(tt.phonetic)
+			ac.AddEntries(entries...)
+
+			dat := NewFilterDat(tt.normalizers...)
+			dat.SetGap(tt.gap)
+			dat.SetPhonetic(tt.phonetic)
+			dat.AddEntries(entries...)
+
+			for _, text := range texts {
+				if got, want := dat.FindMatches(text), ac.FindMatches(text); !reflect.DeepEqual(got, want) {
+					t.Errorf("FindMatches(%q) = %v, want %v", text, got, want)
+				}
+				if got, want := dat.Replace(text, '*', "a"), ac.Replace(text, '*', "a"); got != want {
+					t.Errorf("Replace(%q) = %q, want %q", text, got, want)
+				}
+				if got, want := dat.FindAllBytes([]byte(text)), ac.FindAllBytes([]byte(text)); !reflect.DeepEqual(got, want) {
+					t.Errorf("FindAllBytes(%q) = %v, want %v", text, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_FilterDatRebuild(t *testing.T) {
+	dat := NewFilterDat()
+
+	dat.AddWords(words1...)
+	if res := dat.FindAll("敏感词1敏感词2"); !reflect.DeepEqual(res, []string{"敏感词1", "敏感词2"}) {
+		t.Errorf("FindAll() = %v", res)
+	}
+
+	dat.DelWords("敏感词1")
+	dat.AddWord("感词")
+	if res := dat.FindAll("敏感词1敏感词2"); !reflect.DeepEqual(res, []string{"感词", "敏感词2"}) {
+		t.Errorf("FindAll() after rebuild = %v", res)
+	}
+
+	dat.DelWords(words1...)
+	dat.DelWord("感词")
+	if res := dat.FindAll("敏感词1敏感词2"); res != nil {
+		t.Errorf("FindAll() after delete = %v", res)
+	}
+}
+
+func Test_FilterDatDict(t *testing.T) {
+	data, err := os.ReadFile("../dict/default_dict.txt")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	words := strings.Fields(string(data))
+
+	dfa := NewDfaModel()
+	dfa.AddWords(words...)
+
+	dat := NewFilterDat()
+	dat.AddWords(words...)
+
+	text := strings.Join(words[:1000], "测试")
+	if got, want := dat.FindAllCount(text), dfa.FindAllCount(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllCount() got %d words, want %d", len(got), len(want))
+	}
+}
+
+func Test_FilterDatIncremental(t *testing.T) {
+	texts := []string{
+		"这是敏感词1, 也是敏感词2",
+		"he kills, skills and killing",
+		"敏a感b词2, 敏xx词",
+		"kíll the sheit, pédé",
+	}
+
+	steps := []struct {
+		name string
+		add  []dict.Entry
+		del  []string
+	}{
+		{name: "add", add: []dict.Entry{{Word: "敏感词2"}, {Word: "感词"}, {Word: "kills"}, {Word: "敏词", MaxGap: 2}, {Word: "shit"}, {Word: "pédé"}}},
+		{name: "overlay", add: []dict.Entry{{Word: "敏感词1", Categories: []string{"a"}}, {Word: "kill", Stem: true}}},
+		{name: "replace", add: []dict.Entry{{Word: "感词", Categories: []string{"a"}}, {Word: "pede"}}},
+		{name: "delete", del: []string{"kills", "敏感词2", "pede", "shit"}},
+		{name: "add again", add: []dict.Entry{{Word: "敏感词2"}, {Word: "killing"}}},
+	}
+
+	newDat := func() *FilterDat {
+		dat := NewFilterDat(NewDiacriticNormalizer())
+		dat.SetGap(GapOption{MaxGap: 1})
+		dat.SetPhonetic(true)
+		return dat
+	}
+
+	// full 每一步都重新构建完整的双数组, dat 在第一步之后只做增量修改
+	dat, full := newDat(), newDat()
+	dat.AddEntries(steps[0].add...)
+	dat.compact()
+	full.AddEntries(steps[0].add...)
+	base := dat.base
+
+	for _, step := range steps[1:] {
+		for _, filter := range []*FilterDat{dat, full} {
+			filter.AddEntries(step.add...)
+			filter.DelWords(step.del...)
+		}
+		full.compact()
+
+		if dat.base != base || len(full.trie.Load().tries) != 1 {
+			t.Fatalf("%s: dat rebuilt = %v, full tries = %d", step.name, dat.base != base, len(full.trie.Load().tries))
+		}
+
+		for _, text := range texts {
+			if got, want := dat.FindMatches(text), full.FindMatches(text); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: FindMatches(%q) = %v, want %v", step.name, text, got, want)
+			}
+			if got, want := dat.Replace(text, '*', "a"), full.Replace(text, '*', "a"); got != want {
+				t.Errorf("%s: Replace(%q) = %q, want %q", step.name, text, got, want)
+			}
+		}
+	}
+}
+
+func Test_FilterDatListen(t *testing.T) {
+	dat := NewFilterDat()
+	dat.AddWords(words1...)
+	dat.compact()
+	base := dat.base
+
+	addChan := make(chan dict.Entry)
+	delChan := make(chan string)
+	dat.Listen(addChan, delChan)
+
+	for i := 0; i < 100; i++ {
+		addChan <- dict.Entry{Word: "新词" + strconv.Itoa(i)}
+	}
+	delChan <- "敏感词1"
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !dat.IsSensitive("新词99") || dat.IsSensitive("敏感词1") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Listen() did not apply the changes in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	dat.mu.Lock()
+	rebuilt := dat.base != base
+	dat.mu.Unlock()
+	if rebuilt {
+		t.Errorf("Listen() rebuilt the whole trie before the updates stopped")
+	}
+	if res := dat.FindAll("敏感词1新词42"); !reflect.DeepEqual(res, []string{"新词4", "新词42"}) {
+		t.Errorf("FindAll() = %v", res)
+	}
+}
@@ -0,0 +1,219 @@
+package filter
+
+import (
+	"encoding/base64"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding 文本中可能出现的编码方式
+type Encoding string
+
+const (
+	EncodingUrl     Encoding = "url"     // %E6%95%8F
+	EncodingHtml    Encoding = "html"    // &#25935; &#x654f; &amp;
+	EncodingUnicode Encoding = "unicode" // \u654f \U0000654f
+	EncodingBase64  Encoding = "base64"  // 5pWP5oSf
+)
+
+const (
+	maxDecodeDepth = 3 // 最多解码的嵌套层数
+	minBase64Len   = 8 // 过短的字母数字串不当作 base64
+)
+
+var encodingPatterns = map[Encoding]*regexp.Regexp{
+	EncodingUrl:     regexp.MustCompile(`(?:%[0-9A-Fa-f]{2})+`),
+	EncodingHtml:    regexp.MustCompile(`(?:&(?:#[0-9]{1,7}|#[xX][0-9A-Fa-f]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});)+`),
+	EncodingUnicode: regexp.MustCompile(`(?:\\u[0-9A-Fa-f]{4}|\\U[0-9A-Fa-f]{8})+`),
+	EncodingBase64:  regexp.MustCompile(`[A-Za-z0-9+/\-_]{` + strconv.Itoa(minBase64Len) + `,}={0,2}`),
+}
+
+// DecodedMatch 在解码后的文本中匹配到的敏感词
+// Start 与 End 为最外层编码片段在原文中的区间, Encodings 为由外到内经过的编码层, 原文直接匹配到时为空
+type DecodedMatch struct {
+	Match
+	Encodings []Encoding
+}
+
+// Decoder 解码文本中经过编码的片段后再匹配敏感词, 用于发现下游渲染后才显示出来的敏感词
+type Decoder struct {
+	filter    Filter
+	encodings []Encoding
+}
+
+// NewDecoder 创建解码器, 不指定 encodings 时检测所有支持的编码方式
+func NewDecoder(filter Filter, encodings ...Encoding) *Decoder {
+	if len(encodings) == 0 {
+		encodings = []Encoding{EncodingUrl, EncodingHtml, EncodingUnicode, EncodingBase64}
+	}
+
+	return &Decoder{
+		filter:    filter,
+		encodings: encodings,
+	}
+}
+
+// FindMatches 找到原文及其中编码片段解码后的所有敏感词
+func (d *Decoder) FindMatches(text string) []DecodedMatch {
+	var res []DecodedMatch
+
+	for _, match := range d.filter.FindMatches(text) {
+		res = append(res, DecodedMatch{Match: match})
+	}
+
+	res = d.findEncoded(text, nil, nil, res)
+
+	// 解码后匹配到的敏感词使用原文中的编码片段作为匹配文本
+	runes := []rune(text)
+	for i := range res {
+		if len(res[i].Encodings) > 0 {
+			res[i].Text = string(runes[res[i].Start:res[i].End])
+		}
+	}
+
+	return res
+}
+
+// IsSensitive 原文或解码后的文本中是否有敏感词
+func (d *Decoder) IsSensitive(text string) bool {
+	return len(d.FindMatches(text)) > 0
+}
+
+// findEncoded 解码 text 中的编码片段并匹配敏感词, 再继续向内解码
+// outer 为最外层编码片段在原文中的区间, 解码原文时为 nil
+func (d *Decoder) findEncoded(text string, layers []Encoding, outer *Span, res []DecodedMatch) []DecodedMatch {
+	if len(layers) >= maxDecodeDepth {
+		return res
+	}
+
+	for _, encoding := range d.encodings {
+		pattern, ok := encodingPatterns[encoding]
+		if !ok {
+			continue
+		}
+
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			decoded, ok := decode(encoding, text[loc[0]:loc[1]])
+			if !ok {
+				continue
+			}
+
+			span := Span{
+				Start: utf8.RuneCountInString(text[:loc[0]]),
+				End:   utf8.RuneCountInString(text[:loc[1]]),
+			}
+			if outer != nil {
+				span = *outer
+			}
+
+			next := append(append([]Encoding{}, layers...), encoding)
+
+			for _, match := range d.filter.FindMatches(decoded) {
+				match.Start, match.End = span.Start, span.End
+				res = append(res, DecodedMatch{
+					Match:     match,
+					Encodings: next,
+				})
+			}
+
+			res = d.findEncoded(decoded, next, &span, res)
+		}
+	}
+
+	return res
+}
+
+// decode 解码编码片段, 解码失败或解码结果不是可读文本时返回 false
+func decode(encoding Encoding, encoded string) (string, bool) {
+	var decoded string
+	var err error
+
+	switch encoding {
+	case EncodingUrl:
+		decoded, err = url.PathUnescape(encoded)
+	case EncodingHtml:
+		decoded = html.UnescapeString(encoded)
+	case EncodingUnicode:
+		decoded, err = unescapeUnicode(encoded)
+	case EncodingBase64:
+		decoded, err = decodeBase64(encoded)
+	}
+	if err != nil || decoded == encoded || !isReadable(decoded) {
+		return "", false
+	}
+
+	return decoded, true
+}
+
+// unescapeUnicode 解码 \uXXXX 与 \UXXXXXXXX, \uXXXX 可以是 UTF-16 代理对
+func unescapeUnicode(encoded string) (string, error) {
+	var units []uint16
+	var builder strings.Builder
+
+	flush := func() {
+		builder.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+
+	for len(encoded) > 0 {
+		if encoded[1] == 'u' {
+			unit, err := strconv.ParseUint(encoded[2:6], 16, 16)
+			if err != nil {
+				return "", err
+			}
+			units = append(units, uint16(unit))
+			encoded = encoded[6:]
+			continue
+		}
+
+		r, err := strconv.ParseUint(encoded[2:10], 16, 32)
+		if err != nil {
+			return "", err
+		}
+		flush()
+		builder.WriteRune(rune(r))
+		encoded = encoded[10:]
+	}
+
+	flush()
+
+	return builder.String(), nil
+}
+
+// decodeBase64 依次尝试标准与 URL 安全的 base64 编码
+func decodeBase64(encoded string) (string, error) {
+	var decoded []byte
+	var err error
+
+	trimmed := strings.TrimRight(encoded, "=")
+
+	for _, encoding := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		decoded, err = encoding.DecodeString(trimmed)
+		if err == nil {
+			return string(decoded), nil
+		}
+	}
+
+	return "", err
+}
+
+// isReadable 是否为合法的 UTF-8 文本且不含控制字符
+func isReadable(text string) bool {
+	if !utf8.ValidString(text) {
+		return false
+	}
+
+	for _, r := range text {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
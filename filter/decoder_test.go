@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Decoder(t *testing.T) {
+	type args struct {
+		words []string
+		text  string
+	}
+
+	tests := []struct {
+		name   string
+		args   args
+		result []DecodedMatch
+	}{
+		{
+			name: "url",
+			args: args{
+				words: []string{"敏感"},
+				text:  "看 %E6%95%8F%E6%84%9F",
+			},
+			result: []DecodedMatch{
+				{Match: Match{Word: "敏感", Weight: 1, Text: "%E6%95%8F%E6%84%9F", Start: 2, End: 20, Confidence: 1}, Encodings: []Encoding{EncodingUrl}},
+			},
+		},
+		{
+			name: "html",
+			args: args{
+				words: []string{"敏感"},
+				text:  "敏感&#25935;&#24863;",
+			},
+			result: []DecodedMatch{
+				{Match: Match{Word: "敏感", Weight: 1, Text: "敏感", Start: 0, End: 2, Confidence: 1}},
+				{Match: Match{Word: "敏感", Weight: 1, Text: "&#25935;&#24863;", Start: 2, End: 18, Confidence: 1}, Encodings: []Encoding{EncodingHtml}},
+			},
+		},
+		{
+			name: "unicode",
+			args: args{
+				words: []string{"敏感"},
+				text:  `"\u654f\u611f"`,
+			},
+			result: []DecodedMatch{
+				{Match: Match{Word: "敏感", Weight: 1, Text: `\u654f\u611f`, Start: 1, End: 13, Confidence: 1}, Encodings: []Encoding{EncodingUnicode}},
+			},
+		},
+		{
+			name: "nested",
+			args: args{
+				words: []string{"敏感"},
+				text:  "x JUU2JTk1JThGJUU2JTg0JTlG",
+			},
+			result: []DecodedMatch{
+				{Match: Match{Word: "敏感", Weight: 1, Text: "JUU2JTk1JThGJUU2JTg0JTlG", Start: 2, End: 26, Confidence: 1}, Encodings: []Encoding{EncodingBase64, EncodingUrl}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+
+			filter.AddWords(tt.args.words...)
+
+			result := NewDecoder(filter).FindMatches(tt.args.text)
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("FindMatches() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
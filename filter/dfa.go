@@ -1,24 +1,98 @@
 package filter
 
+import (
+	"io"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
 type dfaNode struct {
-	children map[rune]*dfaNode
+	children nodeChildren[*dfaNode]
 	isLeaf   bool
+	entry    *dict.Entry
 }
 
 func newDfaNode() *dfaNode {
 	return &dfaNode{
-		children: make(map[rune]*dfaNode),
-		isLeaf:   false,
+		isLeaf: false,
+	}
+}
+
+func (n *dfaNode) next(r rune) (*dfaNode, bool) {
+	return n.children.get(r)
+}
+
+func (n *dfaNode) leaf() *dict.Entry {
+	if !n.isLeaf {
+		return nil
+	}
+
+	return n.entry
+}
+
+// walk 遍历结点及其子结点上的敏感词, 词形扩展与敏感词本身会重复出现
+func (n *dfaNode) walk(fn func(entry *dict.Entry)) {
+	if entry := n.leaf(); entry != nil {
+		fn(entry)
+	}
+
+	n.children.each(func(_ rune, child *dfaNode) {
+		child.walk(fn)
+	})
+}
+
+// stats 统计结点及其所有子结点
+func (n *dfaNode) stats(stats *Stats) {
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*n)) + n.children.bytes()
+	if n.children.dense {
+		stats.DenseNodes++
 	}
+
+	n.children.each(func(_ rune, child *dfaNode) {
+		child.stats(stats)
+	})
 }
 
 type DfaModel struct {
-	root *dfaNode
+	root        *dfaNode
+	normalizers normalizers
+	gap         GapOption
+	maxGap      int // 过滤器与所有敏感词中最大的间隔
+	spans       spanLimit
+	longest     int // 最长的敏感词规范化后的文字数量, 删除敏感词时不减小
+	phonetic    *phoneticIndex
 }
 
-func NewDfaModel() *DfaModel {
+// NewDfaModel 创建 DFA 过滤器, 敏感词和文本在匹配前会依次经过 normalizers 规范化
+func NewDfaModel(normalizers ...Normalizer) *DfaModel {
 	return &DfaModel{
-		root: newDfaNode(),
+		root:        newDfaNode(),
+		normalizers: normalizers,
+	}
+}
+
+// SetGap 设置所有敏感词相邻文字之间允许的间隔
+func (m *DfaModel) SetGap(option GapOption) {
+	m.gap = option
+	if option.MaxGap > m.maxGap {
+		m.maxGap = option.MaxGap
+	}
+}
+
+// SetPhonetic 启用或关闭语音匹配, 为拉丁字母组成的敏感词建立 Double Metaphone 索引
+// 读音相同但拼写不同的单词以 PhoneticConfidence 或 PhoneticAlternateConfidence 的可信度返回, 只有 FindMatches 与 Explain 等返回可信度的接口使用
+func (m *DfaModel) SetPhonetic(enabled bool) {
+	if !enabled {
+		m.phonetic = nil
+		return
+	}
+
+	if m.phonetic == nil {
+		m.phonetic = newPhoneticIndex()
+		m.root.walk(m.phonetic.add)
 	}
 }
 
@@ -29,20 +103,59 @@ func (m *DfaModel) AddWords(words ...string) {
 }
 
 func (m *DfaModel) AddWord(word string) {
+	m.AddEntry(dict.Entry{Word: word})
+}
+
+func (m *DfaModel) AddEntries(entries ...dict.Entry) {
+	for _, entry := range entries {
+		m.AddEntry(entry)
+	}
+}
+
+func (m *DfaModel) AddEntry(entry dict.Entry) {
+	// 替换已有的敏感词时一并删除它的词形扩展
+	m.DelWord(entry.Word)
+
+	m.insert(entry.Word, &entry)
+	for _, form := range inflections(&entry) {
+		m.insert(form, &entry)
+	}
+
+	if m.phonetic != nil {
+		m.phonetic.add(&entry)
+	}
+
+	if entry.MaxGap > m.maxGap {
+		m.maxGap = entry.MaxGap
+	}
+	m.spans.add(&entry)
+}
+
+// insert 将 word 插入字典树, 词形扩展不会覆盖字典中的同名敏感词
+func (m *DfaModel) insert(word string, entry *dict.Entry) {
 	now := m.root
-	runes := []rune(word)
+	runes, _ := m.normalizers.normalize([]rune(word))
+
+	if len(runes) > m.longest {
+		m.longest = len(runes)
+	}
 
 	for _, r := range runes {
-		if next, ok := now.children[r]; ok {
+		if next, ok := now.next(r); ok {
 			now = next
 		} else {
 			next = newDfaNode()
-			now.children[r] = next
+			now.children.set(r, next)
 			now = next
 		}
 	}
 
+	if leaf := now.leaf(); leaf != nil && leaf.Word == word && entry.Word != word {
+		return
+	}
+
 	now.isLeaf = true
+	now.entry = entry
 }
 
 func (m *DfaModel) DelWords(words ...string) {
@@ -52,30 +165,57 @@ func (m *DfaModel) DelWords(words ...string) {
 }
 
 func (m *DfaModel) DelWord(word string) {
-	var lastLeaf *dfaNode
-	var lastLeafNextRune rune
+	// 删除的是其他敏感词的词形扩展时, 不删除其他敏感词
+	entry := m.remove(word, nil)
+	if entry == nil || entry.Word != word {
+		return
+	}
+
+	if m.phonetic != nil {
+		m.phonetic.remove(entry)
+	}
+
+	for _, form := range inflections(entry) {
+		m.remove(form, entry)
+	}
+}
+
+// remove 从字典树中删除 word, owner 不为 nil 时只删除属于 owner 的词形扩展, 返回被删除的敏感词
+func (m *DfaModel) remove(word string, owner *dict.Entry) *dict.Entry {
 	now := m.root
-	runes := []rune(word)
+	runes, _ := m.normalizers.normalize([]rune(word))
+	path := make([]*dfaNode, 0, len(runes)+1)
 
 	for _, r := range runes {
-		if next, ok := now.children[r]; !ok {
-			return
-		} else {
-			if now.isLeaf {
-				lastLeaf = now
-				lastLeafNextRune = r
-			}
-			now = next
+		next, ok := now.next(r)
+		if !ok {
+			return nil
 		}
+		path = append(path, now)
+		now = next
+	}
+
+	entry := now.leaf()
+	if owner != nil && entry != owner {
+		return nil
 	}
 
-	delete(lastLeaf.children, lastLeafNextRune)
+	now.isLeaf = false
+	now.entry = nil
+
+	// 从叶子结点向上删除不再属于任何敏感词的结点
+	for i := len(runes) - 1; i >= 0 && !now.isLeaf && now.children.len() == 0; i-- {
+		path[i].children.del(runes[i])
+		now = path[i]
+	}
+
+	return entry
 }
 
-func (m *DfaModel) Listen(addChan, delChan <-chan string) {
+func (m *DfaModel) Listen(addChan <-chan dict.Entry, delChan <-chan string) {
 	go func() {
-		for word := range addChan {
-			m.AddWord(word)
+		for entry := range addChan {
+			m.AddEntry(entry)
 		}
 	}()
 
@@ -86,184 +226,170 @@ func (m *DfaModel) Listen(addChan, delChan <-chan string) {
 	}()
 }
 
-func (m *DfaModel) FindAll(text string) []string {
-	var matches []string // stores words that match in dict
-	var found bool       // if current rune in node's map
-	var now *dfaNode     // current node
-
-	start := 0
-	parent := m.root
-	runes := []rune(text)
-	length := len(runes)
+// scan 先在字典树中查找敏感词, 启用语音匹配时再查找读音相同的敏感词
+func (m *DfaModel) scan(runes []rune, fn scanFunc) {
+	fn = bounded(runes, fn)
 
-	for pos := 0; pos < length; pos++ {
-		now, found = parent.children[runes[pos]]
+	if m.scanTrie(runes, fn) && m.phonetic != nil {
+		m.phonetic.scan(runes, fn)
+	}
+}
 
-		if !found {
-			parent = m.root
-			pos = start
-			start++
-			continue
-		}
+func (m *DfaModel) scanTrie(runes []rune, fn scanFunc) bool {
+	if m.maxGap > 0 {
+		return gapScan(m.root, runes, m.gap, m.maxGap, m.spans.bound(m.gap), false, fn)
+	}
 
-		if now.isLeaf && start <= pos {
-			matches = append(matches, string(runes[start:pos+1]))
-		}
+	length := len(runes)
 
-		if pos == length-1 {
-			parent = m.root
-			pos = start
-			start++
-			continue
-		}
+	for start := 0; start < length; start++ {
+		now := m.root
 
-		parent = now
-	}
+		for pos := start; pos < length; pos++ {
+			next, found := now.next(runes[pos])
+			if !found {
+				break
+			}
 
-	var res []string
-	set := make(map[string]struct{})
+			now = next
 
-	for _, word := range matches {
-		if _, ok := set[word]; !ok {
-			set[word] = struct{}{}
-			res = append(res, word)
+			if now.isLeaf && !fn(start, pos+1, now.entry, ExactConfidence) {
+				return false
+			}
 		}
 	}
 
-	return res
+	return true
 }
 
-func (m *DfaModel) FindAllCount(text string) map[string]int {
-	res := make(map[string]int)
-	var found bool
-	var now *dfaNode
-
-	start := 0
-	parent := m.root
-	runes := []rune(text)
-	length := len(runes)
+// scanBytes 同 scanTrie, 直接解码 UTF-8 字节而不转换为 []rune, start 与 end 为字节下标
+func (m *DfaModel) scanBytes(text []byte, fn scanFunc) {
+	for start := 0; start < len(text); {
+		_, size := utf8.DecodeRune(text[start:])
+		now := m.root
+
+		for pos := start; pos < len(text); {
+			r, n := utf8.DecodeRune(text[pos:])
+			next, found := now.next(r)
+			if !found {
+				break
+			}
 
-	for pos := 0; pos < length; pos++ {
-		now, found = parent.children[runes[pos]]
+			now = next
+			pos += n
 
-		if !found {
-			parent = m.root
-			pos = start
-			start++
-			continue
+			if entry := now.leaf(); entry != nil && (!entry.Stem || isByteBoundary(text, start, pos)) &&
+				!fn(start, pos, entry, ExactConfidence) {
+				return
+			}
 		}
 
-		if now.isLeaf && start <= pos {
-			res[string(runes[start:pos+1])]++
-		}
+		start += size
+	}
+}
 
-		if pos == length-1 {
-			parent = m.root
-			pos = start
-			start++
-			continue
-		}
+// Stats 返回字典树的结点数量与占用内存的估计值
+func (m *DfaModel) Stats() Stats {
+	var stats Stats
+	m.root.stats(&stats)
 
-		parent = now
-	}
+	return stats
+}
 
-	return res
+func (m *DfaModel) FindAll(text string, categories ...string) []string {
+	return findAll(m, m.normalizers, text, categories)
 }
 
-func (m *DfaModel) FindOne(text string) string {
-	var found bool
-	var now *dfaNode
+func (m *DfaModel) FindAllCount(text string, categories ...string) map[string]int {
+	return findAllCount(m, m.normalizers, text, categories)
+}
 
-	start := 0
-	parent := m.root
-	runes := []rune(text)
-	length := len(runes)
+func (m *DfaModel) FindOne(text string, categories ...string) string {
+	return findOne(m, m.normalizers, text, categories)
+}
 
-	for pos := 0; pos < length; pos++ {
-		now, found = parent.children[runes[pos]]
+func (m *DfaModel) IsSensitive(text string, categories ...string) bool {
+	return m.FindOne(text, categories...) != ""
+}
 
-		if !found || (!now.isLeaf && pos == length-1) {
-			parent = m.root
-			pos = start
-			start++
-			continue
-		}
+func (m *DfaModel) Replace(text string, repl rune, categories ...string) string {
+	return mask(m, m.normalizers, text, NewRuneMasker(repl), categories)
+}
 
-		if now.isLeaf && start <= pos {
-			return string(runes[start : pos+1])
-		}
+func (m *DfaModel) ReplaceWith(text string, repl rune, categories ...string) string {
+	return mask(m, m.normalizers, text, NewReplacementMasker(NewRuneMasker(repl)), categories)
+}
 
-		parent = now
-	}
+func (m *DfaModel) Mask(text string, masker Masker, categories ...string) string {
+	return mask(m, m.normalizers, text, masker, categories)
+}
 
-	return ""
+func (m *DfaModel) Annotate(text string, format AnnotateFormat, categories ...string) string {
+	return annotate(m, m.normalizers, text, format, categories)
 }
 
-func (m *DfaModel) IsSensitive(text string) bool {
-	return m.FindOne(text) != ""
+func (m *DfaModel) Remove(text string, categories ...string) string {
+	return mask(m, m.normalizers, text, NewFixedMasker(""), categories)
 }
 
-func (m *DfaModel) Replace(text string, repl rune) string {
-	var found bool
-	var now *dfaNode
+func (m *DfaModel) FindMatches(text string, categories ...string) []Match {
+	return findMatches(m, m.normalizers, text, categories)
+}
 
-	start := 0
-	parent := m.root
-	runes := []rune(text)
-	length := len(runes)
+func (m *DfaModel) Explain(text string, categories ...string) []Explanation {
+	return explain(m, m.normalizers, text, categories)
+}
 
-	for pos := 0; pos < length; pos++ {
-		now, found = parent.children[runes[pos]]
+func (m *DfaModel) ReplaceEdits(text string, repl rune, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, NewRuneMasker(repl), categories)
+}
 
-		if !found || (!now.isLeaf && pos == length-1) {
-			parent = m.root
-			pos = start
-			start++
-			continue
-		}
+func (m *DfaModel) MaskEdits(text string, masker Masker, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, masker, categories)
+}
 
-		if now.isLeaf && start <= pos {
-			for i := start; i <= pos; i++ {
-				runes[i] = repl
-			}
-		}
+func (m *DfaModel) RemoveEdits(text string, categories ...string) (string, []Edit) {
+	return maskEdits(m, m.normalizers, text, NewFixedMasker(""), categories)
+}
 
-		parent = now
-	}
+func (m *DfaModel) ScanReader(reader io.Reader, fn func(Match), categories ...string) error {
+	return scanReader(m, m.normalizers, m.tailLen(), reader, fn, categories)
+}
 
-	return string(runes)
+func (m *DfaModel) NewReplacingWriter(w io.Writer, repl rune, categories ...string) *ReplacingWriter {
+	return newReplacingWriter(m, m.normalizers, m.tailLen(), w, repl, categories)
 }
 
-func (m *DfaModel) Remove(text string) string {
-	var found bool
-	var now *dfaNode
+func (m *DfaModel) tailLen() int {
+	return streamTail(m.longest, m.maxGap)
+}
 
-	start := 0 // 从文本的第几个文字开始匹配
-	parent := m.root
-	runes := []rune(text)
-	length := len(runes)
-	filtered := make([]rune, 0, length)
+func (m *DfaModel) FindAllBytes(text []byte, categories ...string) []string {
+	if !scanBytesSupported(m.normalizers, m.maxGap, m.phonetic, text) {
+		return m.FindAll(string(text), categories...)
+	}
 
-	for pos := 0; pos < length; pos++ {
-		now, found = parent.children[runes[pos]]
+	var words byteWords
+	m.scanBytes(text, func(_, _ int, entry *dict.Entry, _ float64) bool {
+		words.add(entry, categories)
+		return true
+	})
 
-		if !found || (!now.isLeaf && pos == length-1) {
-			filtered = append(filtered, runes[start])
-			parent = m.root
-			pos = start
-			start++
-			continue
-		}
+	return words.res
+}
 
-		if now.isLeaf {
-			start = pos + 1
-			parent = m.root
-		} else {
-			parent = now
-		}
+func (m *DfaModel) ReplaceBytes(text []byte, repl rune, categories ...string) []byte {
+	if !scanBytesSupported(m.normalizers, m.maxGap, m.phonetic, text) {
+		return []byte(m.Replace(string(text), repl, categories...))
 	}
 
-	filtered = append(filtered, runes[start:]...)
+	var spans []Span
+	m.scanBytes(text, func(start, end int, entry *dict.Entry, _ float64) bool {
+		if entry.HasCategory(categories...) {
+			spans = append(spans, Span{Start: start, End: end})
+		}
+		return true
+	})
 
-	return string(filtered)
+	return replaceBytes(text, spans, repl)
 }
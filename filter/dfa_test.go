@@ -0,0 +1,47 @@
+package filter
+
+import "testing"
+
+func Test_DfaOverlaps(t *testing.T) {
+	words := []string{"鸡", "鸡巴", "藏", "藏人", "人权", "刻章", "刻章办", "刻章办证", "办证"}
+
+	tests := []struct {
+		name    string
+		text    string
+		replace string
+		remove  string
+	}{
+		{
+			name:    "longer word",
+			text:    "这鸡巴",
+			replace: "这**",
+			remove:  "这",
+		},
+		{
+			name:    "overlapping words",
+			text:    "藏人权",
+			replace: "***",
+			remove:  "",
+		},
+		{
+			name:    "nested words",
+			text:    "刻章办证",
+			replace: "****",
+			remove:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewDfaModel()
+			filter.AddWords(words...)
+
+			if replaced := filter.Replace(tt.text, '*'); replaced != tt.replace {
+				t.Errorf("Replace() = %v, want %v", replaced, tt.replace)
+			}
+			if removed := filter.Remove(tt.text); removed != tt.remove {
+				t.Errorf("Remove() = %v, want %v", removed, tt.remove)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package filter
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 无法通过 NFD 分解去掉附加符号的拉丁字母
+var diacriticSpecials = map[rune][]rune{
+	'ß': []rune("ss"),
+	'ẞ': []rune("SS"),
+	'æ': []rune("ae"),
+	'Æ': []rune("AE"),
+	'œ': []rune("oe"),
+	'Œ': []rune("OE"),
+	'ø': []rune("o"),
+	'Ø': []rune("O"),
+	'đ': []rune("d"),
+	'Đ': []rune("D"),
+	'ð': []rune("d"),
+	'Ð': []rune("D"),
+	'ł': []rune("l"),
+	'Ł': []rune("L"),
+	'þ': []rune("th"),
+	'Þ': []rune("TH"),
+	'ı': []rune("i"),
+}
+
+// DiacriticNormalizer 附加符号规范化器
+// 对拉丁字母进行 NFD 分解并去掉组合附加符号, 并处理 ß -> ss 等特殊字母, 使 "pédé" 与 "pede" 可以相互匹配
+// 不改变大小写, 需要与 CaseNormalizer 一起使用才能使 "Scheiße" 与 "scheisse" 相互匹配, 也不处理其他文字(如假名的浊点)
+type DiacriticNormalizer struct{}
+
+func NewDiacriticNormalizer() *DiacriticNormalizer {
+	return &DiacriticNormalizer{}
+}
+
+func (n *DiacriticNormalizer) Name() string {
+	return "diacritic"
+}
+
+func (n *DiacriticNormalizer) Normalize(runes []rune) ([]rune, []Span) {
+	res := make([]rune, 0, len(runes))
+	spans := make([]Span, 0, len(runes))
+
+	for i, r := range runes {
+		// 已分解的组合附加符号并入前一个文字
+		if isCombiningDiacritic(r) {
+			if len(spans) > 0 {
+				spans[len(spans)-1].End = i + 1
+			}
+			continue
+		}
+
+		for _, folded := range foldDiacritic(r) {
+			res = append(res, folded)
+			spans = append(spans, Span{Start: i, End: i + 1})
+		}
+	}
+
+	return res, spans
+}
+
+// foldDiacritic 去掉拉丁字母上的附加符号
+func foldDiacritic(r rune) []rune {
+	if r < unicode.MaxASCII || !unicode.Is(unicode.Latin, r) {
+		return []rune{r}
+	}
+
+	if special, ok := diacriticSpecials[r]; ok {
+		return special
+	}
+
+	var res []rune
+
+	for _, d := range norm.NFD.String(string(r)) {
+		if !isCombiningDiacritic(d) {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
+
+// isCombiningDiacritic 是否为组合附加符号
+func isCombiningDiacritic(r rune) bool {
+	switch {
+	case r >= 0x0300 && r <= 0x036F, // Combining Diacritical Marks
+		r >= 0x1AB0 && r <= 0x1AFF, // Combining Diacritical Marks Extended
+		r >= 0x1DC0 && r <= 0x1DFF, // Combining Diacritical Marks Supplement
+		r >= 0x20D0 && r <= 0x20FF, // Combining Diacritical Marks for Symbols
+		r >= 0xFE20 && r <= 0xFE2F: // Combining Half Marks
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package filter
+
+// Edit 和谐敏感词时对原文所做的一处修改, 相互重叠的敏感词合并为一处修改
+// Match 为合并后被替换的区间, 使用其中起始位置靠前且更长的敏感词的信息, Matches 为合并在这一处的所有敏感词
+// NewText 为替换后的文本, NewStart 与 NewEnd 为替换后的文本在新文本中的文字(rune)下标区间
+type Edit struct {
+	Match
+	Matches  []Match
+	NewText  string
+	NewStart int
+	NewEnd   int
+}
+
+// Shift 原文中 offset 处的文字在新文本中的位置, 位于被替换的区间内时返回替换后文本的起始位置
+func Shift(edits []Edit, offset int) int {
+	delta := 0
+
+	for _, edit := range edits {
+		if offset < edit.Start {
+			break
+		}
+		if offset < edit.End {
+			return edit.NewStart
+		}
+		delta = edit.NewEnd - edit.End
+	}
+
+	return offset + delta
+}
+
+// maskEdits 使用 masker 替换敏感词, 并记录每一处修改
+func maskEdits(s scanner, n normalizers, text string, masker Masker, categories []string) (string, []Edit) {
+	var edits []Edit
+
+	runes := []rune(text)
+	res := make([]rune, 0, len(runes))
+	cursor := 0
+
+	for _, group := range overlaps(matches(exactScanner{s}, n, runes, categories)) {
+		cluster := cluster(runes, group)
+		res = append(res, runes[cursor:cluster.Start]...)
+
+		repl := []rune(masker.Mask(cluster))
+		edits = append(edits, Edit{
+			Match:    cluster,
+			Matches:  group,
+			NewText:  string(repl),
+			NewStart: len(res),
+			NewEnd:   len(res) + len(repl),
+		})
+
+		res = append(res, repl...)
+		cursor = cluster.End
+	}
+
+	res = append(res, runes[cursor:]...)
+
+	return string(res), edits
+}
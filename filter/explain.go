@@ -0,0 +1,75 @@
+package filter
+
+import "github.com/sgoware/go-sensitive/dict"
+
+// Explanation 敏感词的匹配过程, 用于处理用户申诉
+type Explanation struct {
+	Match
+	Normalized  string   // 规范化后参与匹配的文本
+	Normalizers []string // 改变了匹配文本的规范化器, 按执行顺序排列
+	Source      string   // 敏感词的来源
+}
+
+// normalizeStage 规范化过程中每一步的结果, spans 指向原文
+type normalizeStage struct {
+	runes []rune
+	spans []Span
+}
+
+// within 返回完全落在原文区间 [start, end) 内的文字
+func (s *normalizeStage) within(start, end int) []rune {
+	var res []rune
+
+	for i, span := range s.spans {
+		if span.Start >= start && span.End <= end {
+			res = append(res, s.runes[i])
+		}
+	}
+
+	return res
+}
+
+func explain(s scanner, n normalizers, text string, categories []string) []Explanation {
+	var res []Explanation
+
+	runes := []rune(text)
+	stages := make([]*normalizeStage, 0, len(n)+1)
+
+	identity := make([]Span, len(runes))
+	for i := range identity {
+		identity[i] = Span{Start: i, End: i + 1}
+	}
+	stages = append(stages, &normalizeStage{runes: runes, spans: identity})
+
+	for i, normalizer := range n {
+		normalized, spans := normalizer.Normalize(stages[i].runes)
+		compose(stages[i].spans, spans)
+		stages = append(stages, &normalizeStage{runes: normalized, spans: spans})
+	}
+
+	last := stages[len(stages)-1]
+
+	s.scan(last.runes, aligned(last.spans, func(start, end int, entry *dict.Entry, confidence float64) bool {
+		if !entry.HasCategory(categories...) {
+			return true
+		}
+
+		originStart, originEnd := origin(last.spans, start, end)
+		explanation := Explanation{
+			Match:      newMatch(runes, entry, originStart, originEnd, confidence),
+			Normalized: string(last.runes[start:end]),
+			Source:     entry.Source,
+		}
+
+		for i, normalizer := range n {
+			if string(stages[i].within(originStart, originEnd)) != string(stages[i+1].within(originStart, originEnd)) {
+				explanation.Normalizers = append(explanation.Normalizers, normalizer.Name())
+			}
+		}
+
+		res = append(res, explanation)
+		return true
+	}))
+
+	return res
+}
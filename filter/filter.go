@@ -1,18 +1,288 @@
 package filter
 
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+// Filter 的所有方法均可指定分类, 指定后只匹配属于任意一个分类的敏感词
 type (
 	Filter interface {
 		// FindAll 找到所有敏感词
-		FindAll(text string) []string
+		FindAll(text string, categories ...string) []string
 		// FindAllCount 找到所有敏感词及出现次数
-		FindAllCount(text string) map[string]int
+		FindAllCount(text string, categories ...string) map[string]int
 		// FindOne 找到一个敏感词
-		FindOne(text string) string
+		FindOne(text string, categories ...string) string
 		// IsSensitive 是否有敏感词
-		IsSensitive(text string) bool
+		IsSensitive(text string, categories ...string) bool
 		// Replace 和谐敏感词
-		Replace(text string, repl rune) string
+		Replace(text string, repl rune, categories ...string) string
+		// ReplaceWith 使用敏感词的替换文本和谐敏感词, 没有替换文本时使用 repl 填充
+		ReplaceWith(text string, repl rune, categories ...string) string
+		// Mask 使用 masker 和谐敏感词
+		Mask(text string, masker Masker, categories ...string) string
+		// Annotate 按 format 标注原文中的敏感词
+		Annotate(text string, format AnnotateFormat, categories ...string) string
+		// Explain 解释每个敏感词的匹配过程
+		Explain(text string, categories ...string) []Explanation
 		// Remove 过滤铭感词
-		Remove(text string) string
+		Remove(text string, categories ...string) string
+		// ReplaceEdits 同 Replace, 并返回对原文所做的修改
+		ReplaceEdits(text string, repl rune, categories ...string) (string, []Edit)
+		// MaskEdits 同 Mask, 并返回对原文所做的修改
+		MaskEdits(text string, masker Masker, categories ...string) (string, []Edit)
+		// RemoveEdits 同 Remove, 并返回对原文所做的修改
+		RemoveEdits(text string, categories ...string) (string, []Edit)
+		// FindMatches 找到所有敏感词及其在原文中的位置
+		FindMatches(text string, categories ...string) []Match
+		// FindAllBytes 同 FindAll, 直接在 UTF-8 字节中查找, 没有敏感词时不分配内存
+		FindAllBytes(text []byte, categories ...string) []string
+		// ReplaceBytes 同 Replace, 直接在 UTF-8 字节中查找, 没有敏感词时返回 text 本身且不分配内存
+		ReplaceBytes(text []byte, repl rune, categories ...string) []byte
+		// ScanReader 按块读取 reader 并查找敏感词, 只保留最长敏感词长度的文字用于匹配跨块的敏感词, 匹配结果的下标为全文中的文字(rune)下标
+		ScanReader(reader io.Reader, fn func(Match), categories ...string) error
+		// NewReplacingWriter 返回和谐敏感词后写入 w 的 Writer, 写入结束后需要调用 Close
+		NewReplacingWriter(w io.Writer, repl rune, categories ...string) *ReplacingWriter
 	}
 )
+
+// Match 匹配到的敏感词, Start 与 End 为原文中的文字(rune)下标区间 [Start, End), Text 为原文中对应的文本
+// Confidence 为匹配的可信度, 字典树中的匹配为 ExactConfidence, 语音匹配更低
+type Match struct {
+	Word        string
+	Categories  []string
+	Weight      float64
+	Replacement string
+	Text        string
+	Start       int
+	End         int
+	Confidence  float64
+}
+
+// ExactConfidence 字典树中的匹配结果的可信度
+const ExactConfidence = 1
+
+// Stats 过滤器的结点数量与占用内存的估计值, 用于比较不同过滤器与字典的内存占用
+type Stats struct {
+	Nodes      int // 结点数量, 包括根结点
+	DenseNodes int // 子结点使用稠密表的结点数量
+	Bytes      int // 结点占用内存的估计值, 不包括敏感词本身
+}
+
+// scanner 在规范化后的文字中查找敏感词, fn 返回 false 时停止查找
+type scanner interface {
+	scan(runes []rune, fn scanFunc)
+}
+
+// scanFunc 接收匹配结果, start 与 end 为规范化文字中的区间, confidence 为匹配的可信度
+type scanFunc func(start, end int, entry *dict.Entry, confidence float64) bool
+
+// exact 只保留字典树中的匹配结果, 不返回可信度的接口(FindAll, IsSensitive, Replace 等)不使用语音匹配等可能误判的结果
+func exact(fn scanFunc) scanFunc {
+	return func(start, end int, entry *dict.Entry, confidence float64) bool {
+		if confidence < ExactConfidence {
+			return true
+		}
+		return fn(start, end, entry, confidence)
+	}
+}
+
+// exactScanner 只返回字典树中的匹配结果的 scanner, 见 exact
+type exactScanner struct {
+	scanner
+}
+
+func (s exactScanner) scan(runes []rune, fn scanFunc) {
+	s.scanner.scan(runes, exact(fn))
+}
+
+func findAll(s scanner, n normalizers, text string, categories []string) []string {
+	var res []string
+	set := make(map[string]struct{})
+
+	runes, spans := n.normalize([]rune(text))
+
+	s.scan(runes, exact(aligned(spans, func(_, _ int, entry *dict.Entry, _ float64) bool {
+		if !entry.HasCategory(categories...) {
+			return true
+		}
+		if _, ok := set[entry.Word]; !ok {
+			set[entry.Word] = struct{}{}
+			res = append(res, entry.Word)
+		}
+		return true
+	})))
+
+	return res
+}
+
+func findAllCount(s scanner, n normalizers, text string, categories []string) map[string]int {
+	res := make(map[string]int)
+
+	runes, spans := n.normalize([]rune(text))
+
+	s.scan(runes, exact(aligned(spans, func(_, _ int, entry *dict.Entry, _ float64) bool {
+		if entry.HasCategory(categories...) {
+			res[entry.Word]++
+		}
+		return true
+	})))
+
+	return res
+}
+
+func findOne(s scanner, n normalizers, text string, categories []string) string {
+	var res string
+
+	runes, spans := n.normalize([]rune(text))
+
+	s.scan(runes, exact(aligned(spans, func(_, _ int, entry *dict.Entry, _ float64) bool {
+		if !entry.HasCategory(categories...) {
+			return true
+		}
+		res = entry.Word
+		return false
+	})))
+
+	return res
+}
+
+func findMatches(s scanner, n normalizers, text string, categories []string) []Match {
+	return matches(s, n, []rune(text), categories)
+}
+
+// matches 返回所有敏感词及其在原文中的位置
+func matches(s scanner, n normalizers, runes []rune, categories []string) []Match {
+	var res []Match
+
+	normalized, spans := n.normalize(runes)
+
+	s.scan(normalized, aligned(spans, func(start, end int, entry *dict.Entry, confidence float64) bool {
+		if !entry.HasCategory(categories...) {
+			return true
+		}
+		start, end = origin(spans, start, end)
+		res = append(res, newMatch(runes, entry, start, end, confidence))
+		return true
+	}))
+
+	return res
+}
+
+// newMatch 创建匹配结果, start 与 end 为原文中的区间
+func newMatch(runes []rune, entry *dict.Entry, start, end int, confidence float64) Match {
+	return Match{
+		Word:        entry.Word,
+		Categories:  entry.Categories,
+		Weight:      entry.GetWeight(),
+		Replacement: entry.Replacement,
+		Text:        string(runes[start:end]),
+		Start:       start,
+		End:         end,
+		Confidence:  confidence,
+	}
+}
+
+// mask 使用 masker 替换敏感词, 相互重叠的敏感词合并为一处后只替换一次
+func mask(s scanner, n normalizers, text string, masker Masker, categories []string) string {
+	res, _ := maskEdits(s, n, text, masker, categories)
+
+	return res
+}
+
+// cluster 合并一组相互重叠的敏感词, 合并后使用起始位置靠前且更长的敏感词的信息, 区间与文本覆盖所有重叠的敏感词
+func cluster(runes []rune, group []Match) Match {
+	res := group[0]
+
+	for _, match := range group[1:] {
+		if match.End > res.End {
+			res.End = match.End
+		}
+	}
+	res.Text = string(runes[res.Start:res.End])
+
+	return res
+}
+
+// overlaps 将相互重叠的敏感词分为一组, 组内按起始位置升序, 长度降序排列
+func overlaps(found []Match) [][]Match {
+	var res [][]Match
+	var end int
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Start != found[j].Start {
+			return found[i].Start < found[j].Start
+		}
+		return found[i].End > found[j].End
+	})
+
+	for _, match := range found {
+		if last := len(res) - 1; last >= 0 && match.Start < end {
+			res[last] = append(res[last], match)
+			if match.End > end {
+				end = match.End
+			}
+			continue
+		}
+
+		res = append(res, []Match{match})
+		end = match.End
+	}
+
+	return res
+}
+
+// listenInterval Listen 批量修改敏感词的间隔
+const listenInterval = 100 * time.Millisecond
+
+// listen 在同一个 goroutine 中读取敏感词的增加与删除, 每隔 interval 批量处理一次, 两个通道都关闭后处理剩余的修改并返回
+// 连续的增加或删除合并为一批, 读到另一种修改时先处理之前的一批, 修改按读到的顺序生效
+func listen(addChan <-chan dict.Entry, delChan <-chan string, interval time.Duration, add func([]dict.Entry), del func([]string)) {
+	var entries []dict.Entry
+	var words []string
+
+	flush := func() {
+		if len(entries) > 0 {
+			add(entries)
+			entries = nil
+		}
+		if len(words) > 0 {
+			del(words)
+			words = nil
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for addChan != nil || delChan != nil {
+		select {
+		case entry, ok := <-addChan:
+			if !ok {
+				addChan = nil
+				continue
+			}
+			if len(words) > 0 {
+				flush()
+			}
+			entries = append(entries, entry)
+		case word, ok := <-delChan:
+			if !ok {
+				delChan = nil
+				continue
+			}
+			if len(entries) > 0 {
+				flush()
+			}
+			words = append(words, word)
+		case <-ticker.C:
+			flush()
+		}
+	}
+
+	flush()
+}
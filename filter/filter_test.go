@@ -0,0 +1,343 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+type testFilter interface {
+	Filter
+	AddEntries(entries ...dict.Entry)
+}
+
+var entries1 = []dict.Entry{
+	{Word: "敏感词1", Categories: []string{"politics"}},
+	{Word: "敏感词2", Categories: []string{"porn", "ads"}},
+	{Word: "敏感词3"},
+}
+
+func Test_Categories(t *testing.T) {
+	type args struct {
+		entries    []dict.Entry
+		text       string
+		categories []string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		findAll []string
+		replace string
+	}{
+		{
+			name: "all",
+			args: args{
+				entries: entries1,
+				text:    text1,
+			},
+			findAll: []string{"敏感词1", "敏感词2", "敏感词3"},
+			replace: "****,这是****,这是****,这是****,这里没有敏感词",
+		},
+		{
+			name: "politics",
+			args: args{
+				entries:    entries1,
+				text:       text1,
+				categories: []string{"politics"},
+			},
+			findAll: []string{"敏感词1"},
+			replace: "****,这是敏感词2,这是敏感词3,这是****,这里没有敏感词",
+		},
+		{
+			name: "ads or abuse",
+			args: args{
+				entries:    entries1,
+				text:       text1,
+				categories: []string{"ads", "abuse"},
+			},
+			findAll: []string{"敏感词2"},
+			replace: "敏感词1,这是****,这是敏感词3,这是敏感词1,这里没有敏感词",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(tt.args.entries...)
+
+				findAll := filter.FindAll(tt.args.text, tt.args.categories...)
+				if !reflect.DeepEqual(findAll, tt.findAll) {
+					t.Errorf("FindAll() = %v, want %v", findAll, tt.findAll)
+				}
+
+				replaced := filter.Replace(tt.args.text, '*', tt.args.categories...)
+				if !reflect.DeepEqual(replaced, tt.replace) {
+					t.Errorf("Replace() = %v, want %v", replaced, tt.replace)
+				}
+			}
+		})
+	}
+}
+
+func Test_MatchCategories(t *testing.T) {
+	filter := NewAcModel()
+
+	filter.AddEntries(entries1...)
+
+	result := filter.FindMatches("这是敏感词2")
+	want := []Match{
+		{Word: "敏感词2", Categories: []string{"porn", "ads"}, Weight: 1, Text: "敏感词2", Start: 2, End: 6, Confidence: 1},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("FindMatches() = %v, want %v", result, want)
+	}
+}
+
+func Test_ReplaceWith(t *testing.T) {
+	type args struct {
+		entries []dict.Entry
+		text    string
+	}
+
+	tests := []struct {
+		name   string
+		args   args
+		result string
+	}{
+		{
+			name: "replacement",
+			args: args{
+				entries: []dict.Entry{
+					{Word: "傻逼", Replacement: "**"},
+					{Word: "competitor.com", Replacement: "[link removed]"},
+					{Word: "敏感词"},
+				},
+				text: "傻逼快去competitor.com看敏感词",
+			},
+			result: "**快去[link removed]看***",
+		},
+		{
+			name: "overlap",
+			args: args{
+				entries: []dict.Entry{
+					{Word: "abc", Replacement: "[x]"},
+					{Word: "cde", Replacement: "[y]"},
+					{Word: "b", Replacement: "[z]"},
+				},
+				text: "_abcdef_",
+			},
+			result: "_[x]f_",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(tt.args.entries...)
+
+				result := filter.ReplaceWith(tt.args.text, '*')
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("ReplaceWith() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
+
+func Test_Mask(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "敏感词", Categories: []string{"politics"}},
+		{Word: "傻逼", Categories: []string{"abuse"}},
+		{Word: "广告"},
+	}
+	text := "敏感词,傻逼,广告"
+
+	tests := []struct {
+		name   string
+		masker Masker
+		result string
+	}{
+		{
+			name:   "rune",
+			masker: NewRuneMasker('*'),
+			result: "***,**,**",
+		},
+		{
+			name:   "fixed",
+			masker: NewFixedMasker("***"),
+			result: "***,***,***",
+		},
+		{
+			name:   "partial",
+			masker: NewPartialMasker('*'),
+			result: "敏*词,傻*,广*",
+		},
+		{
+			name: "category",
+			masker: NewCategoryMasker(map[string]Masker{
+				"politics": NewFixedMasker("[censored]"),
+				"abuse":    NewPartialMasker('*'),
+			}, NewRuneMasker('#')),
+			result: "[censored],傻*,##",
+		},
+		{
+			name: "func",
+			masker: MaskerFunc(func(match Match) string {
+				return "<" + match.Word + ">"
+			}),
+			result: "<敏感词>,<傻逼>,<广告>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries...)
+
+				result := filter.Mask(text, tt.masker)
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("Mask() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
+
+func Test_Edits(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "敏感词", Categories: []string{"politics"}},
+		{Word: "感词"},
+		{Word: "傻逼"},
+	}
+	text := "敏感词,傻逼,广告"
+
+	tests := []struct {
+		name   string
+		masker Masker
+		result string
+		edits  []Edit
+		shifts map[int]int
+	}{
+		{
+			name:   "remove",
+			masker: NewFixedMasker(""),
+			result: ",,广告",
+			edits: []Edit{
+				{
+					Match: Match{Word: "敏感词", Categories: []string{"politics"}, Weight: 1, Text: "敏感词", Start: 0, End: 3, Confidence: 1},
+					Matches: []Match{
+						{Word: "敏感词", Categories: []string{"politics"}, Weight: 1, Text: "敏感词", Start: 0, End: 3, Confidence: 1},
+						{Word: "感词", Weight: 1, Text: "感词", Start: 1, End: 3, Confidence: 1},
+					},
+					NewText:  "",
+					NewStart: 0,
+					NewEnd:   0,
+				},
+				{
+					Match:    Match{Word: "傻逼", Weight: 1, Text: "傻逼", Start: 4, End: 6, Confidence: 1},
+					Matches:  []Match{{Word: "傻逼", Weight: 1, Text: "傻逼", Start: 4, End: 6, Confidence: 1}},
+					NewText:  "",
+					NewStart: 1,
+					NewEnd:   1,
+				},
+			},
+			shifts: map[int]int{0: 0, 2: 0, 3: 0, 4: 1, 6: 1, 7: 2, 9: 4},
+		},
+		{
+			name:   "fixed",
+			masker: NewFixedMasker("[censored]"),
+			result: "[censored],[censored],广告",
+			edits: []Edit{
+				{
+					Match: Match{Word: "敏感词", Categories: []string{"politics"}, Weight: 1, Text: "敏感词", Start: 0, End: 3, Confidence: 1},
+					Matches: []Match{
+						{Word: "敏感词", Categories: []string{"politics"}, Weight: 1, Text: "敏感词", Start: 0, End: 3, Confidence: 1},
+						{Word: "感词", Weight: 1, Text: "感词", Start: 1, End: 3, Confidence: 1},
+					},
+					NewText:  "[censored]",
+					NewStart: 0,
+					NewEnd:   10,
+				},
+				{
+					Match:    Match{Word: "傻逼", Weight: 1, Text: "傻逼", Start: 4, End: 6, Confidence: 1},
+					Matches:  []Match{{Word: "傻逼", Weight: 1, Text: "傻逼", Start: 4, End: 6, Confidence: 1}},
+					NewText:  "[censored]",
+					NewStart: 11,
+					NewEnd:   21,
+				},
+			},
+			shifts: map[int]int{1: 0, 3: 10, 5: 11, 6: 21, 7: 22},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries...)
+
+				result, edits := filter.MaskEdits(text, tt.masker)
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("MaskEdits() result = %v, want %v", result, tt.result)
+				}
+				if !reflect.DeepEqual(edits, tt.edits) {
+					t.Errorf("MaskEdits() edits = %v, want %v", edits, tt.edits)
+				}
+
+				for offset, want := range tt.shifts {
+					if got := Shift(edits, offset); got != want {
+						t.Errorf("Shift(%d) = %v, want %v", offset, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_Annotate(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "abc", Categories: []string{"politics"}},
+		{Word: "cde", Categories: []string{"abuse"}},
+		{Word: "敏感词"},
+	}
+
+	tests := []struct {
+		name   string
+		format AnnotateFormat
+		text   string
+		result string
+	}{
+		{
+			name:   "html",
+			format: NewHtmlFormat(),
+			text:   "<b>敏感词</b>",
+			result: `&lt;b&gt;<mark data-word="敏感词" data-cat="">敏感词</mark>&lt;/b&gt;`,
+		},
+		{
+			name:   "html overlap",
+			format: NewHtmlFormat(),
+			text:   "_abcdef_",
+			result: `_<mark data-word="abc,cde" data-cat="politics,abuse">abcde</mark>f_`,
+		},
+		{
+			name:   "ansi",
+			format: NewAnsiFormat(map[string]string{"abuse": AnsiYellow}),
+			text:   "cd cde 敏感词",
+			result: "cd " + AnsiYellow + "cde" + ansiReset + " " + AnsiRed + "敏感词" + ansiReset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries...)
+
+				result := filter.Annotate(tt.text, tt.format)
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("Annotate() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
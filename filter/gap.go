@@ -0,0 +1,167 @@
+package filter
+
+import "github.com/sgoware/go-sensitive/dict"
+
+// GapOption 允许敏感词相邻两个文字之间出现最多 MaxGap 个任意文字, 如 "敏a感b词"
+// MaxSpan 限制匹配到的文本的总长度, 为 0 时不限制, 敏感词设置了 dict.Entry.MaxGap 或 dict.Entry.MaxSpan 时使用敏感词的设置
+type GapOption struct {
+	MaxGap  int
+	MaxSpan int
+}
+
+// limits 返回敏感词的间隔与总长度限制
+func (o GapOption) limits(entry *dict.Entry) (int, int) {
+	maxGap, maxSpan := o.MaxGap, o.MaxSpan
+
+	if entry.MaxGap > 0 {
+		maxGap = entry.MaxGap
+	}
+	if entry.MaxSpan > 0 {
+		maxSpan = entry.MaxSpan
+	}
+
+	return maxGap, maxSpan
+}
+
+// spanLimit 记录所有敏感词的总长度限制, 用于查找有间隔的敏感词时提前结束过长的分支
+type spanLimit struct {
+	max     int  // 敏感词自己设置的最大总长度
+	inherit bool // 是否有敏感词使用过滤器的总长度限制
+}
+
+func (l *spanLimit) add(entry *dict.Entry) {
+	if entry.MaxSpan == 0 {
+		l.inherit = true
+	} else if entry.MaxSpan > l.max {
+		l.max = entry.MaxSpan
+	}
+}
+
+// merge 合并另一组敏感词的总长度限制
+func (l *spanLimit) merge(other spanLimit) {
+	if other.max > l.max {
+		l.max = other.max
+	}
+	l.inherit = l.inherit || other.inherit
+}
+
+// bound 返回所有敏感词中最大的总长度, 为 0 时不限制
+func (l spanLimit) bound(option GapOption) int {
+	if !l.inherit {
+		return l.max
+	}
+	if option.MaxSpan == 0 {
+		return 0
+	}
+	if option.MaxSpan > l.max {
+		return option.MaxSpan
+	}
+
+	return l.max
+}
+
+// trieNode 字典树结点
+type trieNode[N any] interface {
+	comparable
+	next(r rune) (N, bool)
+	leaf() *dict.Entry
+}
+
+// gapScan 在字典树中查找相邻文字之间最多间隔 maxGap 个文字的敏感词, maxGap 为所有敏感词中最大的间隔, maxSpan 为所有敏感词中最大的总长度
+// 每个敏感词还需要满足自己的间隔与总长度限制, gappedOnly 为 true 时只返回有间隔的敏感词, fn 返回 false 时停止查找并返回 false
+// 同一个起点的查找中, 到达相同结点, 位置与间隔的分支只展开一次, 耗时不会随敏感词长度指数增长
+func gapScan[N trieNode[N]](root N, runes []rune, option GapOption, maxGap, maxSpan int, gappedOnly bool, fn scanFunc) bool {
+	type found struct {
+		end   int
+		entry *dict.Entry
+	}
+
+	type state struct {
+		node     N
+		pos, gap int
+	}
+
+	var seen map[found]struct{}
+	var visited map[state]struct{}
+
+	// report 报告以 start 开头的敏感词, 不同的间隔方式匹配到相同的区间时只报告一次
+	report := func(start, end, gap int, entry *dict.Entry) bool {
+		if gap == 0 && gappedOnly {
+			return true
+		}
+
+		limit, span := option.limits(entry)
+		if gap > limit || (span > 0 && end-start > span) {
+			return true
+		}
+
+		if seen == nil {
+			seen = make(map[found]struct{})
+		}
+		if _, ok := seen[found{end, entry}]; ok {
+			return true
+		}
+		seen[found{end, entry}] = struct{}{}
+
+		return fn(start, end, entry, ExactConfidence)
+	}
+
+	// walk pos 为下一个文字可以出现的最早位置, gap 为目前为止最大的间隔
+	var walk func(node N, start, pos, gap int) bool
+	walk = func(node N, start, pos, gap int) bool {
+		for skip := 0; skip <= maxGap && pos+skip < len(runes); skip++ {
+			end := pos + skip + 1
+			if maxSpan > 0 && end-start > maxSpan {
+				break
+			}
+
+			next, ok := node.next(runes[pos+skip])
+			if !ok {
+				continue
+			}
+
+			nextGap := gap
+			if skip > nextGap {
+				nextGap = skip
+			}
+
+			if visited == nil {
+				visited = make(map[state]struct{})
+			}
+			if _, ok := visited[state{next, end, nextGap}]; ok {
+				continue
+			}
+			visited[state{next, end, nextGap}] = struct{}{}
+
+			if entry := next.leaf(); entry != nil && !report(start, end, nextGap, entry) {
+				return false
+			}
+			if !walk(next, start, end, nextGap) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for start := range runes {
+		next, ok := root.next(runes[start])
+		if !ok {
+			continue
+		}
+
+		seen = nil
+		for key := range visited {
+			delete(visited, key)
+		}
+
+		if entry := next.leaf(); entry != nil && !report(start, start+1, 0, entry) {
+			return false
+		}
+		if !walk(next, start, start+1, 0) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,111 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_Gap(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "敏感词"},
+		{Word: "傻逼", MaxGap: 2},
+		{Word: "aba"},
+	}
+
+	tests := []struct {
+		name   string
+		option GapOption
+		text   string
+		result []string
+	}{
+		{
+			name:   "exact",
+			option: GapOption{MaxGap: 1},
+			text:   "这是敏感词",
+			result: []string{"敏感词"},
+		},
+		{
+			name:   "gap",
+			option: GapOption{MaxGap: 1},
+			text:   "这是敏a感b词",
+			result: []string{"敏a感b词"},
+		},
+		{
+			name:   "gap too large",
+			option: GapOption{MaxGap: 1},
+			text:   "这是敏ab感词",
+			result: nil,
+		},
+		{
+			name:   "span",
+			option: GapOption{MaxGap: 1, MaxSpan: 4},
+			text:   "敏a感词, 敏a感b词",
+			result: []string{"敏a感词"},
+		},
+		{
+			name:   "entry gap",
+			option: GapOption{},
+			text:   "敏a感词, 傻xx逼",
+			result: []string{"傻xx逼"},
+		},
+		{
+			name:   "duplicate paths",
+			option: GapOption{MaxGap: 1},
+			text:   "abba",
+			result: []string{"abba"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dfa, ac := NewDfaModel(), NewAcModel()
+			dfa.SetGap(tt.option)
+			ac.SetGap(tt.option)
+
+			for _, filter := range []testFilter{dfa, ac} {
+				filter.AddEntries(entries...)
+
+				var result []string
+				for _, match := range filter.FindMatches(tt.text) {
+					result = append(result, match.Text)
+				}
+
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("FindMatches() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
+
+func Test_GapLongWord(t *testing.T) {
+	word := strings.Repeat("a", 30) + "b"
+	text := strings.Repeat("a", 200)
+
+	dfa, ac, dat := NewDfaModel(), NewAcModel(), NewFilterDat()
+	for _, filter := range []interface {
+		testFilter
+		SetGap(option GapOption)
+	}{dfa, ac, dat} {
+		filter.SetGap(GapOption{MaxGap: 3})
+		filter.AddEntries(dict.Entry{Word: word})
+
+		done := make(chan []string, 1)
+		go func() {
+			done <- filter.FindAll(text + "b")
+		}()
+
+		select {
+		case result := <-done:
+			if !reflect.DeepEqual(result, []string{word}) {
+				t.Errorf("FindAll() = %v, want %v", result, []string{word})
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("FindAll() did not finish in time")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package filter
+
+import "golang.org/x/text/width"
+
+const (
+	hangulSyllableBase  = 0xAC00
+	hangulSyllableLast  = 0xD7A3
+	hangulVowelCount    = 21
+	hangulFinalCount    = 28
+	hangulChoseongBase  = 0x1100
+	hangulJungseongBase = 0x1161
+	hangulJongseongBase = 0x11A8
+)
+
+var (
+	// 兼容字母表中的初声, 中声, 终声, 顺序与音节编码一致
+	hangulInitials = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	hangulVowels   = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	hangulFinals   = []rune("ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+
+	// 复合字母拆分为键盘上逐个输入的字母
+	hangulCompounds = map[rune][]rune{
+		'ㄳ': []rune("ㄱㅅ"),
+		'ㄵ': []rune("ㄴㅈ"),
+		'ㄶ': []rune("ㄴㅎ"),
+		'ㄺ': []rune("ㄹㄱ"),
+		'ㄻ': []rune("ㄹㅁ"),
+		'ㄼ': []rune("ㄹㅂ"),
+		'ㄽ': []rune("ㄹㅅ"),
+		'ㄾ': []rune("ㄹㅌ"),
+		'ㄿ': []rune("ㄹㅍ"),
+		'ㅀ': []rune("ㄹㅎ"),
+		'ㅄ': []rune("ㅂㅅ"),
+		'ㅘ': []rune("ㅗㅏ"),
+		'ㅙ': []rune("ㅗㅐ"),
+		'ㅚ': []rune("ㅗㅣ"),
+		'ㅝ': []rune("ㅜㅓ"),
+		'ㅞ': []rune("ㅜㅔ"),
+		'ㅟ': []rune("ㅜㅣ"),
+		'ㅢ': []rune("ㅡㅣ"),
+	}
+)
+
+// HangulNormalizer 韩文字母规范化器
+// 将韩文音节拆分为兼容字母, 复合字母拆分为单个字母, 使 "시발", "ㅅㅣㅂㅏㄹ", "시ㅂㅏㄹ" 可以相互匹配
+// 拆分出的每个字母都对应原文中的整个音节, 匹配只能在音节的边界开始和结束, 避免 "옷바구니" 中相邻两个音节的字母组成 "ㅅㅂ"
+// 逐个输入的字母各自是一个音节, 因此 "ㅅㅂ" 仍然可以匹配
+type HangulNormalizer struct{}
+
+func NewHangulNormalizer() *HangulNormalizer {
+	return &HangulNormalizer{}
+}
+
+func (n *HangulNormalizer) Name() string {
+	return "hangul"
+}
+
+func (n *HangulNormalizer) Normalize(runes []rune) ([]rune, []Span) {
+	res := make([]rune, 0, len(runes)*3)
+	spans := make([]Span, 0, len(runes)*3)
+
+	for i, r := range runes {
+		first := len(res)
+
+		for _, jamo := range decomposeHangul(r) {
+			if compound, ok := hangulCompounds[jamo]; ok {
+				for _, c := range compound {
+					res = append(res, c)
+					spans = append(spans, Span{Start: i, End: i + 1, Joined: len(res) > first+1})
+				}
+				continue
+			}
+
+			res = append(res, jamo)
+			spans = append(spans, Span{Start: i, End: i + 1, Joined: len(res) > first+1})
+		}
+	}
+
+	return res, spans
+}
+
+// decomposeHangul 将韩文音节及组合用字母转为兼容字母, 其他字符原样返回
+func decomposeHangul(r rune) []rune {
+	switch {
+	case r >= hangulSyllableBase && r <= hangulSyllableLast:
+		s := r - hangulSyllableBase
+		final := s % hangulFinalCount
+		jamo := []rune{
+			hangulInitials[s/(hangulVowelCount*hangulFinalCount)],
+			hangulVowels[s%(hangulVowelCount*hangulFinalCount)/hangulFinalCount],
+		}
+		if final != 0 {
+			jamo = append(jamo, hangulFinals[final-1])
+		}
+		return jamo
+	case r >= hangulChoseongBase && r < hangulChoseongBase+rune(len(hangulInitials)):
+		return []rune{hangulInitials[r-hangulChoseongBase]}
+	case r >= hangulJungseongBase && r < hangulJungseongBase+rune(len(hangulVowels)):
+		return []rune{hangulVowels[r-hangulJungseongBase]}
+	case r >= hangulJongseongBase && r < hangulJongseongBase+rune(len(hangulFinals)):
+		return []rune{hangulFinals[r-hangulJongseongBase]}
+	case r >= 'ﾠ' && r <= 'ￜ': // 半角韩文字母
+		if wide := width.LookupRune(r).Wide(); wide != 0 {
+			return []rune{wide}
+		}
+	}
+
+	return []rune{r}
+}
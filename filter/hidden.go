@@ -0,0 +1,106 @@
+package filter
+
+import "unicode"
+
+// HiddenMatch 以倒序, 藏头等方式隐藏在文本中的敏感词
+// Positions 按敏感词的顺序记录每个文字在原文中的下标, Start 与 End 为覆盖这些文字的原文区间, Text 为按敏感词顺序重新排列的文字
+type HiddenMatch struct {
+	Match
+	Positions []int
+}
+
+// HiddenDetector 在已有过滤器之上检测倒序书写和藏头诗等隐藏方式
+type HiddenDetector struct {
+	filter Filter
+}
+
+func NewHiddenDetector(filter Filter) *HiddenDetector {
+	return &HiddenDetector{
+		filter: filter,
+	}
+}
+
+// FindReversed 找到倒序书写的敏感词
+func (d *HiddenDetector) FindReversed(text string) []HiddenMatch {
+	runes := []rune(text)
+	positions := make([]int, len(runes))
+	reversed := make([]rune, len(runes))
+
+	for i := range runes {
+		positions[i] = len(runes) - 1 - i
+		reversed[i] = runes[positions[i]]
+	}
+
+	return d.find(reversed, positions)
+}
+
+// FindAcrostic 找到藏在每行第一个文字中的敏感词, tail 为 true 时同时检测每行最后一个文字
+// 行首行尾的空白与标点不计入
+func (d *HiddenDetector) FindAcrostic(text string, tail bool) []HiddenMatch {
+	var heads, tails []rune
+	var headPositions, tailPositions []int
+
+	runes := []rune(text)
+
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && runes[end] != '\n' {
+			end++
+		}
+
+		first, last := start, end-1
+		for first <= last && isBlankOrPunct(runes[first]) {
+			first++
+		}
+		for last >= first && isBlankOrPunct(runes[last]) {
+			last--
+		}
+
+		if first <= last {
+			heads = append(heads, runes[first])
+			headPositions = append(headPositions, first)
+			tails = append(tails, runes[last])
+			tailPositions = append(tailPositions, last)
+		}
+
+		start = end + 1
+	}
+
+	res := d.find(heads, headPositions)
+
+	if tail {
+		res = append(res, d.find(tails, tailPositions)...)
+	}
+
+	return res
+}
+
+// find 匹配重新排列后的文字, positions 为每个文字在原文中的下标
+func (d *HiddenDetector) find(runes []rune, positions []int) []HiddenMatch {
+	var res []HiddenMatch
+
+	for _, match := range d.filter.FindMatches(string(runes)) {
+		hidden := HiddenMatch{
+			Match:     match,
+			Positions: append([]int{}, positions[match.Start:match.End]...),
+		}
+
+		hidden.Start, hidden.End = hidden.Positions[0], hidden.Positions[0]+1
+		for _, pos := range hidden.Positions {
+			if pos < hidden.Start {
+				hidden.Start = pos
+			}
+			if pos+1 > hidden.End {
+				hidden.End = pos + 1
+			}
+		}
+
+		res = append(res, hidden)
+	}
+
+	return res
+}
+
+func isBlankOrPunct(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r)
+}
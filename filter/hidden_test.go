@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_FindReversed(t *testing.T) {
+	filter := NewDfaModel()
+
+	filter.AddWords("敏感词")
+
+	result := NewHiddenDetector(filter).FindReversed("这是词感敏啊")
+	want := []HiddenMatch{
+		{Match: Match{Word: "敏感词", Weight: 1, Text: "敏感词", Start: 2, End: 5, Confidence: 1}, Positions: []int{4, 3, 2}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("FindReversed() = %v, want %v", result, want)
+	}
+}
+
+func Test_FindAcrostic(t *testing.T) {
+	type args struct {
+		text string
+		tail bool
+	}
+
+	tests := []struct {
+		name   string
+		args   args
+		result []HiddenMatch
+	}{
+		{
+			name: "head",
+			args: args{
+				text: "敏而好学,\n  感时花溅泪。\n词穷理屈\n",
+			},
+			result: []HiddenMatch{
+				{Match: Match{Word: "敏感词", Weight: 1, Text: "敏感词", Start: 0, End: 16, Confidence: 1}, Positions: []int{0, 8, 15}},
+			},
+		},
+		{
+			name: "tail",
+			args: args{
+				text: "才思敏\n情深感\n不达词。",
+				tail: true,
+			},
+			result: []HiddenMatch{
+				{Match: Match{Word: "敏感词", Weight: 1, Text: "敏感词", Start: 2, End: 11, Confidence: 1}, Positions: []int{2, 6, 10}},
+			},
+		},
+		{
+			name: "tail disabled",
+			args: args{
+				text: "才思敏\n情深感\n不达词。",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+
+			filter.AddWords("敏感词")
+
+			result := NewHiddenDetector(filter).FindAcrostic(tt.args.text, tt.args.tail)
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("FindAcrostic() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+const (
+	kanaVoicedMark     = '゙' // 合成用浊点
+	kanaSemiVoicedMark = '゚' // 合成用半浊点
+)
+
+// KanaNormalizer 假名规范化器
+// 将半角片假名转为全角, 合成半角输入中分离的浊点/半浊点, 再将片假名统一折叠为平假名
+// 使 "ばか", "バカ", "ﾊﾞｶ" 可以相互匹配
+type KanaNormalizer struct{}
+
+func NewKanaNormalizer() *KanaNormalizer {
+	return &KanaNormalizer{}
+}
+
+func (n *KanaNormalizer) Name() string {
+	return "kana"
+}
+
+func (n *KanaNormalizer) Normalize(runes []rune) ([]rune, []Span) {
+	res := make([]rune, 0, len(runes))
+	spans := make([]Span, 0, len(runes))
+
+	for i, r := range runes {
+		r = widenKana(r)
+
+		if (r == kanaVoicedMark || r == kanaSemiVoicedMark) && len(res) > 0 {
+			if composed, ok := composeKana(res[len(res)-1], r); ok {
+				res[len(res)-1] = composed
+				spans[len(spans)-1].End = i + 1
+				continue
+			}
+		}
+
+		res = append(res, r)
+		spans = append(spans, Span{Start: i, End: i + 1})
+	}
+
+	// 合成浊点后再折叠, "ヷ" 等没有对应平假名的字符保持片假名
+	for i, r := range res {
+		res[i] = foldKana(r)
+	}
+
+	return res, spans
+}
+
+// widenKana 将半角片假名及半角浊点转为全角, 独立的浊点/半浊点转为合成用浊点/半浊点
+func widenKana(r rune) rune {
+	if r >= '｡' && r <= 'ﾟ' {
+		if wide := width.LookupRune(r).Wide(); wide != 0 {
+			r = wide
+		}
+	}
+
+	switch r {
+	case '゛':
+		return kanaVoicedMark
+	case '゜':
+		return kanaSemiVoicedMark
+	}
+
+	return r
+}
+
+// composeKana 合成假名与浊点/半浊点, 如 "カ" + "゙" -> "ガ"
+func composeKana(r, mark rune) (rune, bool) {
+	composed := []rune(norm.NFC.String(string([]rune{r, mark})))
+	if len(composed) != 1 {
+		return 0, false
+	}
+
+	return composed[0], true
+}
+
+// foldKana 将片假名折叠为平假名
+func foldKana(r rune) rune {
+	switch {
+	case r >= 'ァ' && r <= 'ヶ': // ァ ~ ヶ
+		return r - 0x60
+	case r == 'ヽ' || r == 'ヾ': // ヽ ヾ
+		return r - 0x60
+	}
+
+	return r
+}
@@ -0,0 +1,114 @@
+package filter
+
+import "strings"
+
+// Masker 决定敏感词被替换成的文本, 相互重叠的敏感词会合并为一个 Match
+type Masker interface {
+	Mask(match Match) string
+}
+
+// MaskerFunc 使用函数作为 Masker
+type MaskerFunc func(match Match) string
+
+func (f MaskerFunc) Mask(match Match) string {
+	return f(match)
+}
+
+// RuneMasker 将敏感词的每个文字替换为同一个字符, "敏感词" -> "***"
+type RuneMasker struct {
+	repl rune
+}
+
+func NewRuneMasker(repl rune) *RuneMasker {
+	return &RuneMasker{
+		repl: repl,
+	}
+}
+
+func (m *RuneMasker) Mask(match Match) string {
+	return strings.Repeat(string(m.repl), match.End-match.Start)
+}
+
+// FixedMasker 不论敏感词多长都替换为固定的文本, 避免泄露敏感词长度
+type FixedMasker struct {
+	mask string
+}
+
+func NewFixedMasker(mask string) *FixedMasker {
+	return &FixedMasker{
+		mask: mask,
+	}
+}
+
+func (m *FixedMasker) Mask(Match) string {
+	return m.mask
+}
+
+// PartialMasker 保留敏感词的第一个和最后一个文字, "敏感词" -> "敏*词"
+// 两个文字的敏感词只保留第一个文字, 一个文字的敏感词全部替换
+type PartialMasker struct {
+	repl rune
+}
+
+func NewPartialMasker(repl rune) *PartialMasker {
+	return &PartialMasker{
+		repl: repl,
+	}
+}
+
+func (m *PartialMasker) Mask(match Match) string {
+	runes := []rune(match.Text)
+
+	switch len(runes) {
+	case 0:
+		return ""
+	case 1:
+		return string(m.repl)
+	case 2:
+		return string([]rune{runes[0], m.repl})
+	}
+
+	return string(runes[0]) + strings.Repeat(string(m.repl), len(runes)-2) + string(runes[len(runes)-1])
+}
+
+// CategoryMasker 按敏感词的分类选择 Masker, 使用第一个配置了 Masker 的分类, 都没有配置时使用 fallback
+type CategoryMasker struct {
+	maskers  map[string]Masker
+	fallback Masker
+}
+
+func NewCategoryMasker(maskers map[string]Masker, fallback Masker) *CategoryMasker {
+	return &CategoryMasker{
+		maskers:  maskers,
+		fallback: fallback,
+	}
+}
+
+func (m *CategoryMasker) Mask(match Match) string {
+	for _, category := range match.Categories {
+		if masker, ok := m.maskers[category]; ok {
+			return masker.Mask(match)
+		}
+	}
+
+	return m.fallback.Mask(match)
+}
+
+// ReplacementMasker 使用敏感词的替换文本, 没有替换文本时使用 fallback
+type ReplacementMasker struct {
+	fallback Masker
+}
+
+func NewReplacementMasker(fallback Masker) *ReplacementMasker {
+	return &ReplacementMasker{
+		fallback: fallback,
+	}
+}
+
+func (m *ReplacementMasker) Mask(match Match) string {
+	if match.Replacement != "" {
+		return match.Replacement
+	}
+
+	return m.fallback.Mask(match)
+}
@@ -0,0 +1,513 @@
+package filter
+
+import "strings"
+
+// doubleMetaphone 返回英文单词的 Double Metaphone 主编码与备用编码, 发音相近的单词编码相同, 如 "phuck" 与 "fuck" 都为 "FK"
+// 实现参考 Lawrence Philips 的原始算法, 编码不截断长度
+func doubleMetaphone(word string) (string, string) {
+	m := &metaphone{
+		value: []rune(strings.ToUpper(word)),
+	}
+	m.slavoGermanic = strings.ContainsAny(string(m.value), "WK") ||
+		strings.Contains(string(m.value), "CZ") || strings.Contains(string(m.value), "WITZ")
+
+	index := 0
+	if m.contains(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		index = 1
+	}
+	if m.at(0) == 'X' {
+		m.add("S")
+		index = 1
+	}
+
+	for index < len(m.value) {
+		index = m.encode(index)
+	}
+
+	return m.primary.String(), m.alternate.String()
+}
+
+type metaphone struct {
+	value         []rune
+	slavoGermanic bool
+	primary       strings.Builder
+	alternate     strings.Builder
+}
+
+// at 返回 index 处的字母, 越界时返回 0
+func (m *metaphone) at(index int) rune {
+	if index < 0 || index >= len(m.value) {
+		return 0
+	}
+
+	return m.value[index]
+}
+
+// contains 从 start 开始长度为 length 的子串是否为 criteria 之一
+func (m *metaphone) contains(start, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(m.value) {
+		return false
+	}
+
+	sub := string(m.value[start : start+length])
+	for _, c := range criteria {
+		if sub == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *metaphone) isVowel(index int) bool {
+	return strings.ContainsRune("AEIOUY", m.at(index))
+}
+
+// add 同时添加到主编码与备用编码, 给出 alternate 时备用编码使用 alternate
+func (m *metaphone) add(primary string, alternate ...string) {
+	m.primary.WriteString(primary)
+	if len(alternate) > 0 {
+		m.alternate.WriteString(alternate[0])
+	} else {
+		m.alternate.WriteString(primary)
+	}
+}
+
+// skip 下一个字母与当前字母相同时跳过它
+func (m *metaphone) skip(index int, next ...string) int {
+	if len(next) == 0 {
+		next = []string{string(m.at(index))}
+	}
+	if m.contains(index+1, 1, next...) {
+		return index + 2
+	}
+
+	return index + 1
+}
+
+func (m *metaphone) encode(index int) int {
+	switch m.at(index) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		if index == 0 {
+			m.add("A")
+		}
+		return index + 1
+	case 'B':
+		m.add("P")
+		return m.skip(index)
+	case 'Ç':
+		m.add("S")
+		return index + 1
+	case 'C':
+		return m.encodeC(index)
+	case 'D':
+		return m.encodeD(index)
+	case 'F':
+		m.add("F")
+		return m.skip(index)
+	case 'G':
+		return m.encodeG(index)
+	case 'H':
+		if (index == 0 || m.isVowel(index-1)) && m.isVowel(index+1) {
+			m.add("H")
+			return index + 2
+		}
+		return index + 1
+	case 'J':
+		return m.encodeJ(index)
+	case 'K':
+		m.add("K")
+		return m.skip(index)
+	case 'L':
+		return m.encodeL(index)
+	case 'M':
+		m.add("M")
+		if m.at(index+1) == 'M' ||
+			(m.contains(index-1, 3, "UMB") && (index+1 == len(m.value)-1 || m.contains(index+2, 2, "ER"))) {
+			return index + 2
+		}
+		return index + 1
+	case 'N':
+		m.add("N")
+		return m.skip(index)
+	case 'Ñ':
+		m.add("N")
+		return index + 1
+	case 'P':
+		if m.at(index+1) == 'H' {
+			m.add("F")
+			return index + 2
+		}
+		m.add("P")
+		return m.skip(index, "P", "B")
+	case 'Q':
+		m.add("K")
+		return m.skip(index)
+	case 'R':
+		if index == len(m.value)-1 && !m.slavoGermanic && m.contains(index-2, 2, "IE") && !m.contains(index-4, 2, "ME", "MA") {
+			m.add("", "R")
+		} else {
+			m.add("R")
+		}
+		return m.skip(index)
+	case 'S':
+		return m.encodeS(index)
+	case 'T':
+		return m.encodeT(index)
+	case 'V':
+		m.add("F")
+		return m.skip(index)
+	case 'W':
+		return m.encodeW(index)
+	case 'X':
+		if index == 0 {
+			m.add("S")
+			return index + 1
+		}
+		if !(index == len(m.value)-1 && (m.contains(index-3, 3, "IAU", "EAU") || m.contains(index-2, 2, "AU", "OU"))) {
+			m.add("KS")
+		}
+		return m.skip(index, "C", "X")
+	case 'Z':
+		if m.at(index+1) == 'H' {
+			m.add("J")
+			return index + 2
+		}
+		if m.contains(index+1, 2, "ZO", "ZI", "ZA") || (m.slavoGermanic && index > 0 && m.at(index-1) != 'T') {
+			m.add("S", "TS")
+		} else {
+			m.add("S")
+		}
+		return m.skip(index)
+	}
+
+	return index + 1
+}
+
+func (m *metaphone) encodeC(index int) int {
+	switch {
+	case m.conditionC0(index):
+		m.add("K")
+		return index + 2
+	case index == 0 && m.contains(index, 6, "CAESAR"):
+		m.add("S")
+		return index + 2
+	case m.contains(index, 2, "CH"):
+		return m.encodeCH(index)
+	case m.contains(index, 2, "CZ") && !m.contains(index-2, 4, "WICZ"):
+		m.add("S", "X")
+		return index + 2
+	case m.contains(index+1, 3, "CIA"):
+		m.add("X")
+		return index + 3
+	case m.contains(index, 2, "CC") && !(index == 1 && m.at(0) == 'M'):
+		if m.contains(index+2, 1, "I", "E", "H") && !m.contains(index+2, 2, "HU") {
+			if (index == 1 && m.at(index-1) == 'A') || m.contains(index-1, 5, "UCCEE", "UCCES") {
+				m.add("KS")
+			} else {
+				m.add("X")
+			}
+			return index + 3
+		}
+		m.add("K")
+		return index + 2
+	case m.contains(index, 2, "CK", "CG", "CQ"):
+		m.add("K")
+		return index + 2
+	case m.contains(index, 2, "CI", "CE", "CY"):
+		if m.contains(index, 3, "CIO", "CIE", "CIA") {
+			m.add("S", "X")
+		} else {
+			m.add("S")
+		}
+		return index + 2
+	}
+
+	m.add("K")
+	switch {
+	case m.contains(index+1, 2, " C", " Q", " G"):
+		return index + 3
+	case m.contains(index+1, 1, "C", "K", "Q") && !m.contains(index+1, 2, "CE", "CI"):
+		return index + 2
+	}
+
+	return index + 1
+}
+
+func (m *metaphone) conditionC0(index int) bool {
+	switch {
+	case m.contains(index, 4, "CHIA"):
+		return true
+	case index <= 1, m.isVowel(index - 2), !m.contains(index-1, 3, "ACH"):
+		return false
+	}
+
+	c := m.at(index + 2)
+
+	return (c != 'I' && c != 'E') || m.contains(index-2, 6, "BACHER", "MACHER")
+}
+
+func (m *metaphone) encodeCH(index int) int {
+	switch {
+	case index > 0 && m.contains(index, 4, "CHAE"):
+		m.add("K", "X")
+	case m.conditionCH0(index), m.conditionCH1(index):
+		m.add("K")
+	case index > 0 && m.contains(0, 2, "MC"):
+		m.add("K")
+	case index > 0:
+		m.add("X", "K")
+	default:
+		m.add("X")
+	}
+
+	return index + 2
+}
+
+func (m *metaphone) conditionCH0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !m.contains(index+1, 5, "HARAC", "HARIS") && !m.contains(index+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+
+	return !m.contains(0, 5, "CHORE")
+}
+
+func (m *metaphone) conditionCH1(index int) bool {
+	return m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") ||
+		m.contains(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.contains(index+2, 1, "T", "S") ||
+		((m.contains(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(m.contains(index+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == len(m.value)-1))
+}
+
+func (m *metaphone) encodeD(index int) int {
+	switch {
+	case m.contains(index, 2, "DG"):
+		if m.contains(index+2, 1, "I", "E", "Y") {
+			m.add("J")
+			return index + 3
+		}
+		m.add("TK")
+		return index + 2
+	case m.contains(index, 2, "DT", "DD"):
+		m.add("T")
+		return index + 2
+	}
+
+	m.add("T")
+	return index + 1
+}
+
+func (m *metaphone) encodeG(index int) int {
+	switch {
+	case m.at(index+1) == 'H':
+		return m.encodeGH(index)
+	case m.at(index+1) == 'N':
+		switch {
+		case index == 1 && m.isVowel(0) && !m.slavoGermanic:
+			m.add("KN", "N")
+		case !m.contains(index+2, 2, "EY") && m.at(index+1) != 'Y' && !m.slavoGermanic:
+			m.add("N", "KN")
+		default:
+			m.add("KN")
+		}
+		return index + 2
+	case m.contains(index+1, 2, "LI") && !m.slavoGermanic:
+		m.add("KL", "L")
+		return index + 2
+	case index == 0 && (m.at(index+1) == 'Y' ||
+		m.contains(index+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.add("K", "J")
+		return index + 2
+	case (m.contains(index+1, 2, "ER") || m.at(index+1) == 'Y') &&
+		!m.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!m.contains(index-1, 1, "E", "I") &&
+		!m.contains(index-1, 3, "RGY", "OGY"):
+		m.add("K", "J")
+		return index + 2
+	case m.contains(index+1, 1, "E", "I", "Y") || m.contains(index-1, 4, "AGGI", "OGGI"):
+		switch {
+		case m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") || m.contains(index+1, 2, "ET"):
+			m.add("K")
+		case m.contains(index+1, 3, "IER"):
+			m.add("J")
+		default:
+			m.add("J", "K")
+		}
+		return index + 2
+	case m.at(index+1) == 'G':
+		m.add("K")
+		return index + 2
+	}
+
+	m.add("K")
+	return index + 1
+}
+
+func (m *metaphone) encodeGH(index int) int {
+	switch {
+	case index > 0 && !m.isVowel(index-1):
+		m.add("K")
+	case index == 0:
+		if m.at(index+2) == 'I' {
+			m.add("J")
+		} else {
+			m.add("K")
+		}
+	case (index > 1 && m.contains(index-2, 1, "B", "H", "D")) ||
+		(index > 2 && m.contains(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && m.contains(index-4, 1, "B", "H")):
+		// 不发音的 gh, 如 "bough", "broughton"
+	case index > 2 && m.at(index-1) == 'U' && m.contains(index-3, 1, "C", "G", "L", "R", "T"):
+		m.add("F")
+	case index > 0 && m.at(index-1) != 'I':
+		m.add("K")
+	}
+
+	return index + 2
+}
+
+func (m *metaphone) encodeJ(index int) int {
+	if m.contains(index, 4, "JOSE") || m.contains(0, 4, "SAN ") {
+		if (index == 0 && m.at(index+4) == ' ') || len(m.value) == 4 || m.contains(0, 4, "SAN ") {
+			m.add("H")
+		} else {
+			m.add("J", "H")
+		}
+		return index + 1
+	}
+
+	switch {
+	case index == 0:
+		m.add("J", "A")
+	case m.isVowel(index-1) && !m.slavoGermanic && (m.at(index+1) == 'A' || m.at(index+1) == 'O'):
+		m.add("J", "H")
+	case index == len(m.value)-1:
+		m.add("J", "")
+	case !m.contains(index+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.contains(index-1, 1, "S", "K", "L"):
+		m.add("J")
+	}
+
+	return m.skip(index)
+}
+
+func (m *metaphone) encodeL(index int) int {
+	if m.at(index+1) != 'L' {
+		m.add("L")
+		return index + 1
+	}
+
+	// 西班牙语的 "ll" 不发音, 如 "cabrillo", "gallegos"
+	if (index == len(m.value)-3 && m.contains(index-1, 4, "ILLO", "ILLA", "ALLE")) ||
+		((m.contains(len(m.value)-2, 2, "AS", "OS") || m.contains(len(m.value)-1, 1, "A", "O")) && m.contains(index-1, 4, "ALLE")) {
+		m.add("L", "")
+	} else {
+		m.add("L")
+	}
+
+	return index + 2
+}
+
+func (m *metaphone) encodeS(index int) int {
+	switch {
+	case m.contains(index-1, 3, "ISL", "YSL"):
+		return index + 1
+	case index == 0 && m.contains(index, 5, "SUGAR"):
+		m.add("X", "S")
+		return index + 1
+	case m.contains(index, 2, "SH"):
+		if m.contains(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		return index + 2
+	case m.contains(index, 3, "SIO", "SIA") || m.contains(index, 4, "SIAN"):
+		if m.slavoGermanic {
+			m.add("S")
+		} else {
+			m.add("S", "X")
+		}
+		return index + 3
+	case (index == 0 && m.contains(index+1, 1, "M", "N", "L", "W")) || m.contains(index+1, 1, "Z"):
+		m.add("S", "X")
+		return m.skip(index, "Z")
+	case m.contains(index, 2, "SC"):
+		return m.encodeSC(index)
+	}
+
+	if index == len(m.value)-1 && m.contains(index-2, 2, "AI", "OI") {
+		m.add("", "S")
+	} else {
+		m.add("S")
+	}
+
+	return m.skip(index, "S", "Z")
+}
+
+func (m *metaphone) encodeSC(index int) int {
+	switch {
+	case m.at(index+2) == 'H':
+		switch {
+		case m.contains(index+3, 2, "ER", "EN"):
+			m.add("X", "SK")
+		case m.contains(index+3, 2, "OO", "UY", "ED", "EM"):
+			m.add("SK")
+		case index == 0 && !m.isVowel(3) && m.at(3) != 'W':
+			m.add("X", "S")
+		default:
+			m.add("X")
+		}
+	case m.contains(index+2, 1, "I", "E", "Y"):
+		m.add("S")
+	default:
+		m.add("SK")
+	}
+
+	return index + 3
+}
+
+func (m *metaphone) encodeT(index int) int {
+	switch {
+	case m.contains(index, 4, "TION"), m.contains(index, 3, "TIA", "TCH"):
+		m.add("X")
+		return index + 3
+	case m.contains(index, 2, "TH") || m.contains(index, 3, "TTH"):
+		if m.contains(index+2, 2, "OM", "AM") || m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") {
+			m.add("T")
+		} else {
+			m.add("0", "T")
+		}
+		return index + 2
+	}
+
+	m.add("T")
+	return m.skip(index, "T", "D")
+}
+
+func (m *metaphone) encodeW(index int) int {
+	switch {
+	case m.contains(index, 2, "WR"):
+		m.add("R")
+		return index + 2
+	case index == 0 && (m.isVowel(index+1) || m.contains(index, 2, "WH")):
+		if m.isVowel(index + 1) {
+			m.add("A", "F")
+		} else {
+			m.add("A")
+		}
+		return index + 1
+	case (index == len(m.value)-1 && m.isVowel(index-1)) ||
+		m.contains(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.contains(0, 3, "SCH"):
+		m.add("", "F")
+		return index + 1
+	case m.contains(index, 4, "WICZ", "WITZ"):
+		m.add("TS", "FX")
+		return index + 4
+	}
+
+	return index + 1
+}
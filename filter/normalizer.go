@@ -0,0 +1,75 @@
+package filter
+
+import "github.com/sgoware/go-sensitive/dict"
+
+// Span 规范化后的文字在输入中对应的区间 [Start, End)
+// Joined 表示该文字与前一个文字由输入中的同一个文字拆分而来(如韩文音节拆分出的字母), 匹配不能在两者之间开始或结束
+type Span struct {
+	Start  int
+	End    int
+	Joined bool
+}
+
+// Normalizer 文本规范化器
+// 插入敏感词和扫描文本时使用同一套规范化, 使同一个词的不同写法可以相互匹配
+type Normalizer interface {
+	// Name 规范化器名称, 用于解释匹配结果
+	Name() string
+	// Normalize 返回规范化后的文字, 以及每个文字在输入中对应的区间(区间不能为空)
+	Normalize(runes []rune) ([]rune, []Span)
+}
+
+type normalizers []Normalizer
+
+// normalize 依次执行所有规范化器, 返回的区间均指向原始输入
+// 没有规范化器时返回原文字与 nil 区间
+func (n normalizers) normalize(runes []rune) ([]rune, []Span) {
+	var spans []Span
+
+	for _, normalizer := range n {
+		var next []Span
+
+		runes, next = normalizer.Normalize(runes)
+		if spans != nil {
+			compose(spans, next)
+		}
+		spans = next
+	}
+
+	return runes, spans
+}
+
+// compose 将 next 中指向上一步结果的区间改为指向 prev 所指向的原文, 上一步中与前一个文字相连的文字仍然相连
+func compose(prev, next []Span) {
+	for i, span := range next {
+		next[i] = Span{
+			Start:  prev[span.Start].Start,
+			End:    prev[span.End-1].End,
+			Joined: span.Joined || prev[span.Start].Joined,
+		}
+	}
+}
+
+// origin 将规范化文字中的区间 [start, end) 映射回原文
+func origin(spans []Span, start, end int) (int, int) {
+	if spans == nil {
+		return start, end
+	}
+
+	return spans[start].Start, spans[end-1].End
+}
+
+// aligned 忽略在输入中的一个文字内部开始或结束的匹配, 如 "옷바구니" 中由 "옷" 的终声与 "바" 的初声组成的 "ㅅㅂ"
+func aligned(spans []Span, fn scanFunc) scanFunc {
+	if spans == nil {
+		return fn
+	}
+
+	return func(start, end int, entry *dict.Entry, confidence float64) bool {
+		if spans[start].Joined || (end < len(spans) && spans[end].Joined) {
+			return true
+		}
+
+		return fn(start, end, entry, confidence)
+	}
+}
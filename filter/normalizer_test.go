@@ -0,0 +1,289 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_KanaNormalizer(t *testing.T) {
+	type args struct {
+		words []string
+		text  string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		findAll []string
+		replace string
+	}{
+		{
+			name: "hiragana",
+			args: args{
+				words: []string{"バカ"},
+				text:  "お前はばかだ",
+			},
+			findAll: []string{"バカ"},
+			replace: "お前は**だ",
+		},
+		{
+			name: "halfwidth",
+			args: args{
+				words: []string{"ばか"},
+				text:  "お前はﾊﾞｶだ",
+			},
+			findAll: []string{"ばか"},
+			replace: "お前は***だ",
+		},
+		{
+			name: "semi voiced",
+			args: args{
+				words: []string{"パチンコ"},
+				text:  "ﾊﾟﾁﾝｺ屋",
+			},
+			findAll: []string{"パチンコ"},
+			replace: "*****屋",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []interface {
+				Filter
+				AddWords(words ...string)
+			}{
+				NewDfaModel(NewKanaNormalizer()),
+				NewAcModel(NewKanaNormalizer()),
+			} {
+				filter.AddWords(tt.args.words...)
+
+				findAll := filter.FindAll(tt.args.text)
+				if !reflect.DeepEqual(findAll, tt.findAll) {
+					t.Errorf("FindAll() = %v, want %v", findAll, tt.findAll)
+				}
+
+				replaced := filter.Replace(tt.args.text, '*')
+				if !reflect.DeepEqual(replaced, tt.replace) {
+					t.Errorf("Replace() = %v, want %v", replaced, tt.replace)
+				}
+			}
+		})
+	}
+}
+
+func Test_HangulNormalizer(t *testing.T) {
+	type args struct {
+		words []string
+		text  string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		findAll []string
+		replace string
+	}{
+		{
+			name: "decomposed",
+			args: args{
+				words: []string{"시발"},
+				text:  "ㅅㅣㅂㅏㄹ 놈",
+			},
+			findAll: []string{"시발"},
+			replace: "***** 놈",
+		},
+		{
+			name: "mixed",
+			args: args{
+				words: []string{"시발"},
+				text:  "이 시ㅂㅏㄹ",
+			},
+			findAll: []string{"시발"},
+			replace: "이 ****",
+		},
+		{
+			name: "syllable boundary",
+			args: args{
+				words: []string{"바보"},
+				text:  "너 바봌ㅋ, 바보ㅋ",
+			},
+			findAll: []string{"바보"},
+			replace: "너 바봌ㅋ, **ㅋ",
+		},
+		{
+			name: "across syllables",
+			args: args{
+				words: []string{"ㅅㅂ"},
+				text:  "옷바구니, ㅅㅂ",
+			},
+			findAll: []string{"ㅅㅂ"},
+			replace: "옷바구니, **",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []interface {
+				Filter
+				AddWords(words ...string)
+			}{
+				NewDfaModel(NewHangulNormalizer()),
+				NewAcModel(NewHangulNormalizer()),
+			} {
+				filter.AddWords(tt.args.words...)
+
+				findAll := filter.FindAll(tt.args.text)
+				if !reflect.DeepEqual(findAll, tt.findAll) {
+					t.Errorf("FindAll() = %v, want %v", findAll, tt.findAll)
+				}
+
+				replaced := filter.Replace(tt.args.text, '*')
+				if !reflect.DeepEqual(replaced, tt.replace) {
+					t.Errorf("Replace() = %v, want %v", replaced, tt.replace)
+				}
+			}
+		})
+	}
+}
+
+func Test_DiacriticNormalizer(t *testing.T) {
+	type args struct {
+		words []string
+		text  string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		findAll []string
+		replace string
+	}{
+		{
+			name: "accent",
+			args: args{
+				words: []string{"pede"},
+				text:  "quel pédé",
+			},
+			findAll: []string{"pede"},
+			replace: "quel ****",
+		},
+		{
+			name: "sharp s",
+			args: args{
+				words: []string{"scheiße"},
+				text:  "so eine scheisse!",
+			},
+			findAll: []string{"scheiße"},
+			replace: "so eine ********!",
+		},
+		{
+			name: "combining mark",
+			args: args{
+				words: []string{"pédé"},
+				text:  "pe\u0301de\u0301 ok",
+			},
+			findAll: []string{"pédé"},
+			replace: "****** ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []interface {
+				Filter
+				AddWords(words ...string)
+			}{
+				NewDfaModel(NewDiacriticNormalizer()),
+				NewAcModel(NewDiacriticNormalizer()),
+			} {
+				filter.AddWords(tt.args.words...)
+
+				findAll := filter.FindAll(tt.args.text)
+				if !reflect.DeepEqual(findAll, tt.findAll) {
+					t.Errorf("FindAll() = %v, want %v", findAll, tt.findAll)
+				}
+
+				replaced := filter.Replace(tt.args.text, '*')
+				if !reflect.DeepEqual(replaced, tt.replace) {
+					t.Errorf("Replace() = %v, want %v", replaced, tt.replace)
+				}
+			}
+		})
+	}
+}
+
+func Test_Explain(t *testing.T) {
+	filter := NewAcModel(NewDiacriticNormalizer(), NewKanaNormalizer())
+
+	filter.AddEntries(
+		dict.Entry{Word: "pede", Source: "dict.txt"},
+		dict.Entry{Word: "ばか", Source: "memory"},
+	)
+
+	result := filter.Explain("quel pédé, ﾊﾞｶ")
+	want := []Explanation{
+		{
+			Match:       Match{Word: "pede", Weight: 1, Text: "pédé", Start: 5, End: 9, Confidence: 1},
+			Normalized:  "pede",
+			Normalizers: []string{"diacritic"},
+			Source:      "dict.txt",
+		},
+		{
+			Match:       Match{Word: "ばか", Weight: 1, Text: "ﾊﾞｶ", Start: 11, End: 14, Confidence: 1},
+			Normalized:  "ばか",
+			Normalizers: []string{"kana"},
+			Source:      "memory",
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Explain() = %v, want %v", result, want)
+	}
+}
+
+func Test_CaseNormalizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		word    string
+		text    string
+		findAll []string
+		replace string
+	}{
+		{
+			name:    "case",
+			word:    "Shit",
+			text:    "oh SHIT",
+			findAll: []string{"Shit"},
+			replace: "oh ****",
+		},
+		{
+			name:    "case and diacritic",
+			word:    "scheisse",
+			text:    "so eine Scheiße!",
+			findAll: []string{"scheisse"},
+			replace: "so eine *******!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{
+				NewDfaModel(NewDiacriticNormalizer(), NewCaseNormalizer()),
+				NewAcModel(NewDiacriticNormalizer(), NewCaseNormalizer()),
+			} {
+				filter.AddEntries(dict.Entry{Word: tt.word})
+
+				findAll := filter.FindAll(tt.text)
+				if !reflect.DeepEqual(findAll, tt.findAll) {
+					t.Errorf("FindAll() = %v, want %v", findAll, tt.findAll)
+				}
+
+				replaced := filter.Replace(tt.text, '*')
+				if !reflect.DeepEqual(replaced, tt.replace) {
+					t.Errorf("Replace() = %v, want %v", replaced, tt.replace)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+const (
+	PhoneticConfidence          = 0.8 // 与敏感词的 Double Metaphone 主编码相同时的可信度
+	PhoneticAlternateConfidence = 0.6 // 只有备用编码相同时的可信度
+	PhoneticWeakConfidence      = 0.5 // 与敏感词的编辑距离大于 1 时的可信度, 不区分主编码与备用编码
+)
+
+const (
+	phoneticMinKey  = 2 // 编码过短的敏感词(如 "a")容易误判, 不加入语音索引
+	phoneticMinWord = 4 // 过短的单词(如 "ass" 与 "is")读音相近的常用词太多, 敏感词与文本中的单词都至少需要这么多文字
+)
+
+// phoneticMaxDistance 返回读音相同的单词与敏感词之间允许的最大编辑距离, 拼写差别过大的单词(如 "fake" 与 "fuck")多半只是读音碰巧相同的普通单词
+func phoneticMaxDistance(word string) int {
+	return utf8.RuneCountInString(word) / 2
+}
+
+// phoneticIndex 以 Double Metaphone 编码索引由拉丁字母组成的单个单词的敏感词, 用于匹配 "phuck", "sheit" 等读音相近的拼写
+type phoneticIndex struct {
+	entries map[string][]*dict.Entry  // 编码 -> 敏感词
+	keys    map[*dict.Entry][2]string // 敏感词 -> 主编码与备用编码
+}
+
+func newPhoneticIndex() *phoneticIndex {
+	return &phoneticIndex{
+		entries: make(map[string][]*dict.Entry),
+		keys:    make(map[*dict.Entry][2]string),
+	}
+}
+
+func (p *phoneticIndex) add(entry *dict.Entry) {
+	if _, ok := p.keys[entry]; ok || !isLatinWord(entry.Word) || utf8.RuneCountInString(entry.Word) < phoneticMinWord {
+		return
+	}
+
+	primary, alternate := doubleMetaphone(entry.Word)
+	if len(primary) < phoneticMinKey {
+		return
+	}
+
+	p.keys[entry] = [2]string{primary, alternate}
+
+	p.entries[primary] = append(p.entries[primary], entry)
+	if alternate != primary && len(alternate) >= phoneticMinKey {
+		p.entries[alternate] = append(p.entries[alternate], entry)
+	}
+}
+
+func (p *phoneticIndex) remove(entry *dict.Entry) {
+	keys, ok := p.keys[entry]
+	if !ok {
+		return
+	}
+
+	delete(p.keys, entry)
+
+	for _, key := range keys {
+		entries := p.entries[key][:0]
+		for _, e := range p.entries[key] {
+			if e != entry {
+				entries = append(entries, e)
+			}
+		}
+
+		if len(entries) == 0 {
+			delete(p.entries, key)
+		} else {
+			p.entries[key] = entries
+		}
+	}
+}
+
+// scan 按单词查找读音相同的敏感词, 与敏感词拼写相同的单词已由字典树匹配, 不再重复返回
+func (p *phoneticIndex) scan(runes []rune, fn scanFunc) bool {
+	return scanPhonetic([]*phoneticIndex{p}, runes, fn)
+}
+
+// scanPhonetic 同 phoneticIndex.scan, 同时在多个索引中查找, 每个编码按 indexes 的顺序返回敏感词
+// 常用单词(见 phoneticCommonWords)与拼写差别过大的单词不会匹配, 编辑距离大于 1 的匹配可信度为 PhoneticWeakConfidence
+func scanPhonetic(indexes []*phoneticIndex, runes []rune, fn scanFunc) bool {
+	for start := 0; start < len(runes); {
+		if !unicode.Is(unicode.Latin, runes[start]) {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && unicode.Is(unicode.Latin, runes[end]) {
+			end++
+		}
+
+		if end-start < phoneticMinWord {
+			start = end
+			continue
+		}
+
+		token := string(runes[start:end])
+		lower := strings.ToLower(token)
+		if _, ok := phoneticCommonWords[lower]; ok {
+			start = end
+			continue
+		}
+
+		primary, alternate := doubleMetaphone(token)
+		seen := make(map[*dict.Entry]struct{})
+
+		for _, key := range [2]string{primary, alternate} {
+			for _, p := range indexes {
+				for _, entry := range p.entries[key] {
+					if _, ok := seen[entry]; ok || entry.Word == token {
+						continue
+					}
+					seen[entry] = struct{}{}
+
+					distance := editDistance(lower, strings.ToLower(entry.Word))
+					if distance > phoneticMaxDistance(entry.Word) {
+						continue
+					}
+
+					confidence := PhoneticAlternateConfidence
+					if p.keys[entry][0] == primary {
+						confidence = PhoneticConfidence
+					}
+					if distance > 1 {
+						confidence = PhoneticWeakConfidence
+					}
+
+					if !fn(start, end, entry, confidence) {
+						return false
+					}
+				}
+			}
+		}
+
+		start = end
+	}
+
+	return true
+}
+
+// editDistance 返回 a 与 b 按文字计算的 Levenshtein 编辑距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cur := row[j]
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			row[j] = prev + cost
+			if cur+1 < row[j] {
+				row[j] = cur + 1
+			}
+			if row[j-1]+1 < row[j] {
+				row[j] = row[j-1] + 1
+			}
+			prev = cur
+		}
+	}
+
+	return row[len(rb)]
+}
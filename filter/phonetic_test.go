@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_doubleMetaphone(t *testing.T) {
+	tests := []struct {
+		word      string
+		primary   string
+		alternate string
+	}{
+		{word: "fuck", primary: "FK", alternate: "FK"},
+		{word: "phuck", primary: "FK", alternate: "FK"},
+		{word: "shit", primary: "XT", alternate: "XT"},
+		{word: "sheit", primary: "XT", alternate: "XT"},
+		{word: "Smith", primary: "SM0", alternate: "XMT"},
+		{word: "Schmidt", primary: "XMT", alternate: "SMT"},
+		{word: "Knight", primary: "NT", alternate: "NT"},
+		{word: "Xavier", primary: "SF", alternate: "SFR"},
+		{word: "Jose", primary: "HS", alternate: "HS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			primary, alternate := doubleMetaphone(tt.word)
+			if primary != tt.primary || alternate != tt.alternate {
+				t.Errorf("doubleMetaphone() = %v, %v, want %v, %v", primary, alternate, tt.primary, tt.alternate)
+			}
+		})
+	}
+}
+
+type phoneticFilter interface {
+	testFilter
+	SetPhonetic(enabled bool)
+}
+
+func Test_Phonetic(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "fuck"},
+		{Word: "shit"},
+		{Word: "敏感词"},
+	}
+	text := "phuck this sheit, fuck 敏感词"
+
+	tests := []struct {
+		name    string
+		enabled bool
+		result  []Match
+	}{
+		{
+			name:    "disabled",
+			enabled: false,
+			result: []Match{
+				{Word: "fuck", Weight: 1, Text: "fuck", Start: 18, End: 22, Confidence: 1},
+				{Word: "敏感词", Weight: 1, Text: "敏感词", Start: 23, End: 26, Confidence: 1},
+			},
+		},
+		{
+			name:    "enabled",
+			enabled: true,
+			result: []Match{
+				{Word: "fuck", Weight: 1, Text: "fuck", Start: 18, End: 22, Confidence: 1},
+				{Word: "敏感词", Weight: 1, Text: "敏感词", Start: 23, End: 26, Confidence: 1},
+				{Word: "fuck", Weight: 1, Text: "phuck", Start: 0, End: 5, Confidence: PhoneticWeakConfidence},
+				{Word: "shit", Weight: 1, Text: "sheit", Start: 11, End: 16, Confidence: PhoneticConfidence},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []phoneticFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries[:1]...)
+				filter.SetPhonetic(tt.enabled)
+				filter.AddEntries(entries[1:]...)
+
+				result := filter.FindMatches(text)
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("FindMatches() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
+
+func Test_PhoneticExact(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "ass"},
+		{Word: "fuck"},
+		{Word: "shit"},
+		{Word: "hell"},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		matches int
+	}{
+		{name: "common words", text: "this is a fake sheet on the hill, holy folk", matches: 0},
+		{name: "short words", text: "it is fine", matches: 0},
+		{name: "sound alike", text: "phuck this", matches: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []phoneticFilter{NewDfaModel(), NewAcModel(), NewFilterDat()} {
+				filter.SetPhonetic(true)
+				filter.AddEntries(entries...)
+
+				if result := filter.FindAll(tt.text); result != nil {
+					t.Errorf("FindAll() = %v, want nil", result)
+				}
+				if filter.IsSensitive(tt.text) {
+					t.Errorf("IsSensitive() = true, want false")
+				}
+				if replaced := filter.Replace(tt.text, '*'); replaced != tt.text {
+					t.Errorf("Replace() = %v, want %v", replaced, tt.text)
+				}
+				if result := filter.FindMatches(tt.text); len(result) != tt.matches {
+					t.Errorf("FindMatches() = %v, want %d matches", result, tt.matches)
+				}
+			}
+		})
+	}
+}
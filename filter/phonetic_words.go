@@ -0,0 +1,46 @@
+package filter
+
+import "strings"
+
+// phoneticCommonWords 是不参与语音匹配的常用英文单词, 它们与敏感词读音相近且拼写接近(如 "sheet" 与 "shit", "hill" 与 "hell"), 但几乎总是正常用法
+// 只收录至少 phoneticMinWord 个字母的单词, 更短的单词本就不参与语音匹配
+var phoneticCommonWords = wordSet(`
+	able about above after again against almost alone along also always among another answer anyone anything around away
+	back bank base bath beach bean bear beat became because become been beer before begin being believe bell belt below
+	bench best better between bird bitter black blind block blood blow blue boat body bone book born both bottle bottom
+	bowl boxes break bring brother brown build bullet burn busy butter button buys cake call came camp cant card care carry
+	case cash cast catch cause cell cent chair chance change cheap check cheek cheese chest child chin choose church city
+	claim class clean clear clock close cloth cloud coat code coin cold come comb common cook cool copy corn cost could
+	count country cover cozy crab crack crop cross crowd cube cull curl cute dance dark date daughter dead deal dear death
+	deck deep desk dial dice diet dime dirt dish dock does dome done door double down draw dream dress drink drive drop
+	duck dull duty each early earth east easy edge else even ever every face fact fail fair fall fame family fans farm fast
+	father fault fear feed feel feet fell felt fence field fight figure fill film final find fine fire firm first fish five
+	fixed flag flat floor flow fold folk food foot form fort four free fresh from front full fund funny gain game garden
+	gate gave general girl give glad glass goal goes gold gone good grass great green grow guard guess gulf hair half hall
+	hand happy hard hate have head heal hear heart heat heel held hello help here hero high hill hire hold hole holy home
+	hope horse host hour house huge hull idea inch into iron island item join joke jump just keep kept kick kind king kiss
+	kitchen knee knew knock know lady laid lake land large last late lead leaf learn least leave left less letter life lift
+	light like line lion list little live lock long look lord lose loss lost loud love luck made mail main make male mall
+	many mark mass master meal mean meat meet melt mess metal middle might mile milk mind mine miss mock money month moon
+	more most mother move much music must name near neck need never news next nice night none noon nose note nothing now
+	number ocean offer office often once only open other over pace page paid pain pair palace paper park part pass past
+	path peace peak pick piece pile pipe place plan plant play please plus pocket point pole pool poor pose post pound
+	power press price print puck pull pure push quick quiet race rain reach read real rest rice rich ride right ring rise
+	river road rock roll roof room rose round rule safe said sail sale salt same sand save school seat seed seem seen self
+	sell send sent shall shape share sharp sheep sheet shell shine ship shirt shoe shoot shop shore short shot should shout
+	show shut sick side sign silk silt since sing sister site size skin slip slit slot slow small smile snow soft soil sold
+	some song soon sort soul sound south space speak speed spell spend spot star start state stay step still stock stone
+	stop store story street such suit sure sweet table tack tail take talk tall tank task team tell tend test text than
+	that them then there these they thick thin thing think this those three through tick time tire told tomb tone took
+	tool town tree trip truck true tuck turn twin under until upon used very view visit voice wait walk wall want ware
+	warm wash watch water wave week well went were west what when where which while white whole wide wife wild will wind
+	wine wire wish with woke wood word wore work world would write year young your
+`)
+
+func wordSet(words string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range strings.Fields(words) {
+		set[word] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,182 @@
+package filter
+
+import "sort"
+
+// Phrase 共现规则, Terms 为字典中的敏感词, 至少 Quorum 个不同的敏感词出现在 Window 个文字内时命中
+// Quorum 为 0 时需要全部敏感词, Window 为 0 时不限制距离, Ordered 为 true 时敏感词需要按 Terms 中的顺序出现且互不重叠
+type Phrase struct {
+	Name    string
+	Terms   []string
+	Window  int
+	Quorum  int
+	Ordered bool
+}
+
+// PhraseMatch 命中的共现规则, Matches 为组成这次命中的敏感词, Start 与 End 为它们在原文中的区间
+type PhraseMatch struct {
+	Phrase  *Phrase
+	Matches []Match
+	Start   int
+	End     int
+}
+
+// PhraseMatcher 在敏感词的匹配结果上计算共现规则, 所有规则共用一次扫描
+type PhraseMatcher struct {
+	filter  Filter
+	phrases []*Phrase
+}
+
+func NewPhraseMatcher(filter Filter, phrases ...*Phrase) *PhraseMatcher {
+	return &PhraseMatcher{
+		filter:  filter,
+		phrases: phrases,
+	}
+}
+
+func (m *PhraseMatcher) AddPhrases(phrases ...*Phrase) {
+	m.phrases = append(m.phrases, phrases...)
+}
+
+// Find 找到文本中命中的共现规则
+func (m *PhraseMatcher) Find(text string, categories ...string) []PhraseMatch {
+	return m.Evaluate(m.filter.FindMatches(text, categories...))
+}
+
+// Evaluate 在已有的匹配结果上计算共现规则, 可以与其他功能共用 FindMatches 的结果
+func (m *PhraseMatcher) Evaluate(found []Match) []PhraseMatch {
+	var res []PhraseMatch
+
+	for _, phrase := range m.phrases {
+		res = append(res, phrase.evaluate(found)...)
+	}
+
+	return res
+}
+
+// evaluate 从左到右查找命中, 同一条规则的命中互不重叠
+func (p *Phrase) evaluate(found []Match) []PhraseMatch {
+	var res []PhraseMatch
+
+	index := make(map[string]int, len(p.Terms))
+	for i, term := range p.Terms {
+		if _, ok := index[term]; !ok {
+			index[term] = i
+		}
+	}
+
+	quorum := p.Quorum
+	if quorum <= 0 || quorum > len(index) {
+		quorum = len(index)
+	}
+
+	var candidates []Match
+	for _, match := range found {
+		if _, ok := index[match.Word]; ok {
+			candidates = append(candidates, match)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Start < candidates[j].Start
+	})
+
+	for i := 0; i < len(candidates); {
+		window := p.window(candidates[i:])
+
+		var chosen []Match
+		if p.Ordered {
+			chosen = ordered(window, index)
+		} else {
+			chosen = unordered(window)
+		}
+
+		if len(chosen) < quorum {
+			i++
+			continue
+		}
+
+		hit := PhraseMatch{
+			Phrase:  p,
+			Matches: chosen,
+			Start:   chosen[0].Start,
+		}
+		for _, match := range chosen {
+			if match.End > hit.End {
+				hit.End = match.End
+			}
+		}
+		res = append(res, hit)
+
+		for i < len(candidates) && candidates[i].Start < hit.End {
+			i++
+		}
+	}
+
+	return res
+}
+
+// window 以第一个敏感词为起点, 返回完整落在 Window 个文字内的敏感词
+func (p *Phrase) window(candidates []Match) []Match {
+	if p.Window <= 0 {
+		return candidates
+	}
+
+	var res []Match
+	start := candidates[0].Start
+
+	for _, match := range candidates {
+		if match.Start-start >= p.Window {
+			break
+		}
+		if match.End-start <= p.Window {
+			res = append(res, match)
+		}
+	}
+
+	return res
+}
+
+// unordered 返回每个敏感词第一次出现的位置
+func unordered(window []Match) []Match {
+	var res []Match
+	set := make(map[string]struct{})
+
+	for _, match := range window {
+		if _, ok := set[match.Word]; !ok {
+			set[match.Word] = struct{}{}
+			res = append(res, match)
+		}
+	}
+
+	return res
+}
+
+// ordered 返回以第一个敏感词开头, 按规则顺序出现且互不重叠的最长敏感词序列
+func ordered(window []Match, index map[string]int) []Match {
+	lengths := make([]int, len(window))
+	prev := make([]int, len(window))
+	best := 0
+
+	lengths[0], prev[0] = 1, -1
+
+	for j := 1; j < len(window); j++ {
+		for k := 0; k < j; k++ {
+			if lengths[k] == 0 || index[window[k].Word] >= index[window[j].Word] || window[k].End > window[j].Start {
+				continue
+			}
+			if lengths[k]+1 > lengths[j] {
+				lengths[j], prev[j] = lengths[k]+1, k
+			}
+		}
+		if lengths[j] > lengths[best] {
+			best = j
+		}
+	}
+
+	res := make([]Match, lengths[best])
+	for i, j := len(res)-1, best; j >= 0; i, j = i-1, prev[j] {
+		res[i] = window[j]
+	}
+
+	return res
+}
@@ -0,0 +1,84 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_PhraseMatcher(t *testing.T) {
+	var entries []dict.Entry
+	for _, word := range []string{"代开", "发票", "冰毒", "出售", "联系"} {
+		entries = append(entries, dict.Entry{Word: word})
+	}
+
+	invoice := &Phrase{Name: "invoice", Terms: []string{"代开", "发票"}, Window: 10}
+	orderedInvoice := &Phrase{Name: "ordered", Terms: []string{"代开", "发票"}, Window: 10, Ordered: true}
+	drug := &Phrase{Name: "drug", Terms: []string{"冰毒", "出售", "联系"}, Window: 8, Quorum: 2}
+
+	tests := []struct {
+		name    string
+		phrases []*Phrase
+		text    string
+		result  [][]string
+	}{
+		{
+			name:    "near",
+			phrases: []*Phrase{invoice},
+			text:    "专业代开增值税发票",
+			result:  [][]string{{"代开", "发票"}},
+		},
+		{
+			name:    "far",
+			phrases: []*Phrase{invoice},
+			text:    "代开会议通知, 下周一统一报销发票",
+			result:  nil,
+		},
+		{
+			name:    "unordered",
+			phrases: []*Phrase{invoice, orderedInvoice},
+			text:    "发票可以代开",
+			result:  [][]string{{"发票", "代开"}},
+		},
+		{
+			name:    "ordered",
+			phrases: []*Phrase{orderedInvoice},
+			text:    "发票可以代开, 也可以代开发票",
+			result:  [][]string{{"代开", "发票"}},
+		},
+		{
+			name:    "quorum",
+			phrases: []*Phrase{drug},
+			text:    "出售冰毒, 价格好说. 有意请联系",
+			result:  [][]string{{"出售", "冰毒"}},
+		},
+		{
+			name:    "repeat",
+			phrases: []*Phrase{invoice},
+			text:    "代开发票, 代开发票",
+			result:  [][]string{{"代开", "发票"}, {"代开", "发票"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries...)
+
+				var result [][]string
+				for _, hit := range NewPhraseMatcher(filter, tt.phrases...).Find(tt.text) {
+					var terms []string
+					for _, match := range hit.Matches {
+						terms = append(terms, match.Word)
+					}
+					result = append(result, terms)
+				}
+
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("Find() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
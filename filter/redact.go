@@ -0,0 +1,106 @@
+package filter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"regexp"
+
+	"github.com/sgoware/go-sensitive/vault"
+)
+
+const (
+	tokenPrefix = "⟦r:"
+	tokenSuffix = "⟧"
+	tokenBytes  = 4
+)
+
+var tokenPattern = regexp.MustCompile(tokenPrefix + `[0-9a-f]+` + tokenSuffix)
+
+// Redactor 可还原的脱敏, 将敏感词替换为 ⟦r:8f3a01bc⟧ 形式的令牌, 并在 vault 中记录原文
+type Redactor struct {
+	filter Filter
+	vault  vault.Vault
+}
+
+func NewRedactor(filter Filter, vault vault.Vault) *Redactor {
+	return &Redactor{
+		filter: filter,
+		vault:  vault,
+	}
+}
+
+// Redact 将敏感词替换为令牌, 相互重叠的敏感词合并为一个令牌
+func (r *Redactor) Redact(text string, categories ...string) (string, error) {
+	var err error
+
+	redacted := r.filter.Mask(text, MaskerFunc(func(match Match) string {
+		if err != nil {
+			return match.Text
+		}
+
+		var token string
+
+		token, err = r.token()
+		if err != nil {
+			return match.Text
+		}
+
+		err = r.vault.Put(token, match.Text)
+
+		return token
+	}), categories...)
+	if err != nil {
+		return "", err
+	}
+
+	return redacted, nil
+}
+
+// Restore 将令牌还原为原文, vault 中不存在的令牌保持不变
+func (r *Redactor) Restore(text string) (string, error) {
+	var err error
+
+	restored := tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if err != nil {
+			return token
+		}
+
+		original, getErr := r.vault.Get(token)
+		if getErr != nil {
+			if !errors.Is(getErr, vault.ErrNotFound) {
+				err = getErr
+			}
+			return token
+		}
+
+		return original
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return restored, nil
+}
+
+// token 生成 vault 中不存在的随机令牌
+func (r *Redactor) token() (string, error) {
+	buf := make([]byte, tokenBytes)
+
+	for {
+		_, err := rand.Read(buf)
+		if err != nil {
+			return "", err
+		}
+
+		token := tokenPrefix + hex.EncodeToString(buf) + tokenSuffix
+
+		_, err = r.vault.Get(token)
+		if errors.Is(err, vault.ErrNotFound) {
+			return token, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/vault"
+)
+
+func Test_Redactor(t *testing.T) {
+	fileVault, err := vault.NewFileModel(filepath.Join(t.TempDir(), "vault.jsonl"))
+	if err != nil {
+		t.Fatalf("open file vault failed, err: %v", err)
+	}
+	defer func() {
+		_ = fileVault.Close()
+	}()
+
+	tests := []struct {
+		name  string
+		vault vault.Vault
+	}{
+		{
+			name:  "memory",
+			vault: vault.NewMemoryModel(),
+		},
+		{
+			name:  "file",
+			vault: fileVault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+
+			filter.AddWords(words1...)
+
+			redactor := NewRedactor(filter, tt.vault)
+
+			redacted, err := redactor.Redact(text1)
+			if err != nil {
+				t.Fatalf("Redact() err: %v", err)
+			}
+			if filter.IsSensitive(redacted) {
+				t.Errorf("Redact() = %v, still sensitive", redacted)
+			}
+			if len(tokenPattern.FindAllString(redacted, -1)) != 4 {
+				t.Errorf("Redact() = %v, want 4 tokens", redacted)
+			}
+
+			restored, err := redactor.Restore(redacted + "⟦r:00000000⟧")
+			if err != nil {
+				t.Fatalf("Restore() err: %v", err)
+			}
+			if !reflect.DeepEqual(restored, text1+"⟦r:00000000⟧") {
+				t.Errorf("Restore() = %v, want %v", restored, text1+"⟦r:00000000⟧")
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+// Rule 编译后的布尔规则
+type Rule struct {
+	dict.Rule
+	expr  dict.Expr
+	terms map[string]struct{}
+}
+
+// CompileRule 编译规则, 表达式的语法见 dict.Rule
+func CompileRule(rule dict.Rule) (*Rule, error) {
+	expr, err := dict.CompileExpr(rule.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Rule{
+		Rule:  rule,
+		expr:  expr,
+		terms: make(map[string]struct{}),
+	}
+	for _, term := range expr.Terms() {
+		res.terms[term] = struct{}{}
+	}
+
+	return res, nil
+}
+
+// RuleMatch 命中的规则, Matches 为文本中出现的属于该规则的敏感词
+type RuleMatch struct {
+	Rule    *Rule
+	Matches []Match
+}
+
+// RuleMatcher 在敏感词的匹配结果上计算布尔规则, 所有规则共用一次扫描
+// 规则中的词不需要在字典中, RuleMatcher 为所有规则中的词建立单独的索引, 如 "NOT 反诈" 中的 "反诈" 不会被 Filter 和谐
+type RuleMatcher struct {
+	filter Filter
+
+	mu          sync.RWMutex
+	rules       []*Rule
+	normalizers []Normalizer
+	terms       *AcModel // 规则中的词
+}
+
+func NewRuleMatcher(filter Filter, rules ...*Rule) *RuleMatcher {
+	m := &RuleMatcher{
+		filter: filter,
+		terms:  NewAcModel(),
+	}
+	m.AddRules(rules...)
+
+	return m
+}
+
+// SetNormalizers 设置匹配规则中的词时使用的规范化器, 通常与 filter 相同
+func (m *RuleMatcher) SetNormalizers(normalizers ...Normalizer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.normalizers = normalizers
+	m.index()
+}
+
+// index 由所有规则中的词重新建立索引, 调用时需持有 mu
+func (m *RuleMatcher) index() {
+	terms := NewAcModel(m.normalizers...)
+	for _, rule := range m.rules {
+		for term := range rule.terms {
+			terms.addEntry(dict.Entry{Word: term})
+		}
+	}
+	terms.buildFailPointers()
+
+	m.terms = terms
+}
+
+// AddRules 添加规则, 替换同名的规则
+func (m *RuleMatcher) AddRules(rules ...*Rule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, rule := range rules {
+		m.rules = append(m.deleted(rule.Name), rule)
+	}
+	m.index()
+}
+
+func (m *RuleMatcher) DelRules(names ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range names {
+		m.rules = m.deleted(name)
+	}
+	m.index()
+}
+
+// deleted 返回去掉名为 name 的规则后的规则列表
+func (m *RuleMatcher) deleted(name string) []*Rule {
+	res := m.rules[:0]
+
+	for _, rule := range m.rules {
+		if rule.Name != name {
+			res = append(res, rule)
+		}
+	}
+
+	return res
+}
+
+// Listen 监听数据源中规则的变化, 无法编译的规则会被忽略
+func (m *RuleMatcher) Listen(addChan <-chan dict.Rule, delChan <-chan string) {
+	go func() {
+		for rule := range addChan {
+			if compiled, err := CompileRule(rule); err == nil {
+				m.AddRules(compiled)
+			}
+		}
+	}()
+
+	go func() {
+		for name := range delChan {
+			m.DelRules(name)
+		}
+	}()
+}
+
+// Find 找到文本命中的规则, 规则中的词没有分类, 因此不按分类过滤
+func (m *RuleMatcher) Find(text string) []RuleMatch {
+	m.mu.RLock()
+	terms := m.terms
+	m.mu.RUnlock()
+
+	found := append(m.filter.FindMatches(text), terms.FindMatches(text)...)
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Start < found[j].Start
+	})
+
+	return m.Evaluate(found)
+}
+
+// Evaluate 在已有的匹配结果上计算规则, 可以与其他功能共用 FindMatches 的结果
+// found 中只有字典中的敏感词时, 不在字典中的规则词视为没有出现, 这种情况请使用 Find
+func (m *RuleMatcher) Evaluate(found []Match) []RuleMatch {
+	var res []RuleMatch
+
+	set := make(map[string]struct{}, len(found))
+	for _, match := range found {
+		set[match.Word] = struct{}{}
+	}
+
+	has := func(term string) bool {
+		_, ok := set[term]
+		return ok
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, rule := range m.rules {
+		if !rule.expr.Eval(has) {
+			continue
+		}
+
+		hit := RuleMatch{
+			Rule: rule,
+		}
+		for _, match := range found {
+			if _, ok := rule.terms[match.Word]; ok && !containsMatch(hit.Matches, match) {
+				hit.Matches = append(hit.Matches, match)
+			}
+		}
+		res = append(res, hit)
+	}
+
+	return res
+}
+
+// containsMatch 同一个词在同一个位置可能同时由字典与规则索引匹配到, 只保留一次
+func containsMatch(found []Match, match Match) bool {
+	for _, m := range found {
+		if m.Word == match.Word && m.Start == match.Start && m.End == match.End {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_CompileRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "term", expr: "赌博"},
+		{name: "nested", expr: "(赌博 OR 博彩) AND (充值 OR 返利) AND NOT 反诈"},
+		{name: "quoted", expr: `"AND" OR "x y"`},
+		{name: "empty", expr: " ", wantErr: true},
+		{name: "missing paren", expr: "(赌博 OR 博彩", wantErr: true},
+		{name: "extra paren", expr: "赌博)", wantErr: true},
+		{name: "dangling operator", expr: "赌博 AND", wantErr: true},
+		{name: "adjacent terms", expr: "赌博 博彩", wantErr: true},
+		{name: "unterminated quote", expr: `"赌博`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileRule(dict.Rule{Name: tt.name, Expr: tt.expr})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CompileRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_RuleMatcher(t *testing.T) {
+	var entries []dict.Entry
+	// 反诈与 AND 不在字典中, 只出现在规则里
+	for _, word := range []string{"赌博", "博彩", "充值", "返利"} {
+		entries = append(entries, dict.Entry{Word: word})
+	}
+
+	var rules []*Rule
+	for _, rule := range []dict.Rule{
+		{Name: "gambling", Expr: "(赌博 OR 博彩) AND (充值 OR 返利) AND NOT 反诈"},
+		{Name: "rebate", Expr: "NOT NOT 返利"},
+		{Name: "quoted", Expr: `"AND" AND 充值`},
+	} {
+		compiled, err := CompileRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, compiled)
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		result map[string][]string
+	}{
+		{
+			name:   "fired",
+			text:   "博彩网站充值送返利",
+			result: map[string][]string{"gambling": {"博彩", "充值", "返利"}, "rebate": {"返利"}},
+		},
+		{
+			name:   "negated",
+			text:   "反诈提醒: 博彩充值都是骗局",
+			result: map[string][]string{},
+		},
+		{
+			name:   "partial",
+			text:   "赌博违法",
+			result: map[string][]string{},
+		},
+		{
+			name:   "quoted",
+			text:   "AND 充值",
+			result: map[string][]string{"quoted": {"AND", "充值"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []testFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries...)
+
+				result := make(map[string][]string)
+				for _, hit := range NewRuleMatcher(filter, rules...).Find(tt.text) {
+					var words []string
+					for _, match := range hit.Matches {
+						words = append(words, match.Word)
+					}
+					result[hit.Rule.Name] = words
+				}
+
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("Find() = %v, want %v", result, tt.result)
+				}
+
+				if filter.IsSensitive("反诈") {
+					t.Errorf("IsSensitive() = true, rule terms should not be added to the filter")
+				}
+			}
+		})
+	}
+}
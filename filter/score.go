@@ -0,0 +1,106 @@
+package filter
+
+import "math"
+
+// Verdict 根据风险分数给出的处理结论
+type Verdict int
+
+const (
+	VerdictPass   Verdict = iota // 通过
+	VerdictReview                // 人工审核
+	VerdictBlock                 // 拦截
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictPass:
+		return "pass"
+	case VerdictReview:
+		return "review"
+	case VerdictBlock:
+		return "block"
+	}
+
+	return "unknown"
+}
+
+type ScoreOption struct {
+	ReviewThreshold float64            // 分数达到该值时需要人工审核, 为 0 时不启用
+	BlockThreshold  float64            // 分数达到该值时拦截, 为 0 时不启用
+	CategoryCaps    map[string]float64 // 每个分类最多贡献的分数
+	RepeatDecay     float64            // 同一敏感词第 n 次出现时权重乘以 RepeatDecay^(n-1), 为 0 时不衰减
+	MinConfidence   float64            // 只统计可信度不低于该值的匹配, 为 0 时取 ExactConfidence, 即不统计语音匹配等不确定的结果
+}
+
+// Score 文本的风险分数
+type Score struct {
+	Total      float64
+	Categories map[string]float64 // 每个分类贡献的分数
+	Verdict    Verdict
+}
+
+// Scorer 根据匹配到的敏感词权重计算文本的风险分数, 敏感词的权重会乘以匹配的可信度
+type Scorer struct {
+	filter Filter
+	option ScoreOption
+}
+
+func NewScorer(filter Filter, option ScoreOption) *Scorer {
+	return &Scorer{
+		filter: filter,
+		option: option,
+	}
+}
+
+// Score 计算文本的风险分数, 可以只统计指定分类的敏感词
+func (s *Scorer) Score(text string, categories ...string) *Score {
+	res := &Score{
+		Categories: make(map[string]float64),
+	}
+	counts := make(map[string]int)
+
+	minConfidence := s.option.MinConfidence
+	if minConfidence == 0 {
+		minConfidence = ExactConfidence
+	}
+
+	for _, match := range s.filter.FindMatches(text, categories...) {
+		if match.Confidence < minConfidence {
+			continue
+		}
+
+		weight := match.Weight * match.Confidence
+
+		if s.option.RepeatDecay != 0 {
+			weight *= math.Pow(s.option.RepeatDecay, float64(counts[match.Word]))
+		}
+		counts[match.Word]++
+
+		// 属于多个分类时, 受剩余额度最少的分类限制
+		for _, category := range match.Categories {
+			if limit, ok := s.option.CategoryCaps[category]; ok {
+				weight = math.Min(weight, math.Max(limit-res.Categories[category], 0))
+			}
+		}
+
+		for _, category := range match.Categories {
+			res.Categories[category] += weight
+		}
+		res.Total += weight
+	}
+
+	res.Verdict = s.verdict(res.Total)
+
+	return res
+}
+
+func (s *Scorer) verdict(total float64) Verdict {
+	switch {
+	case s.option.BlockThreshold != 0 && total >= s.option.BlockThreshold:
+		return VerdictBlock
+	case s.option.ReviewThreshold != 0 && total >= s.option.ReviewThreshold:
+		return VerdictReview
+	}
+
+	return VerdictPass
+}
@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_Score(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "赌博", Categories: []string{"gambling"}, Weight: 4},
+		{Word: "博彩", Categories: []string{"gambling"}, Weight: 4},
+		{Word: "傻逼", Categories: []string{"abuse"}, Weight: 2},
+		{Word: "广告"},
+	}
+
+	tests := []struct {
+		name   string
+		option ScoreOption
+		text   string
+		result *Score
+	}{
+		{
+			name: "pass",
+			option: ScoreOption{
+				ReviewThreshold: 3,
+				BlockThreshold:  8,
+			},
+			text: "这是广告",
+			result: &Score{
+				Total:      1,
+				Categories: map[string]float64{},
+				Verdict:    VerdictPass,
+			},
+		},
+		{
+			name: "review",
+			option: ScoreOption{
+				ReviewThreshold: 3,
+				BlockThreshold:  8,
+			},
+			text: "傻逼傻逼",
+			result: &Score{
+				Total:      4,
+				Categories: map[string]float64{"abuse": 4},
+				Verdict:    VerdictReview,
+			},
+		},
+		{
+			name: "category cap",
+			option: ScoreOption{
+				ReviewThreshold: 3,
+				BlockThreshold:  8,
+				CategoryCaps:    map[string]float64{"gambling": 6},
+			},
+			text: "赌博和博彩, 傻逼",
+			result: &Score{
+				Total:      8,
+				Categories: map[string]float64{"gambling": 6, "abuse": 2},
+				Verdict:    VerdictBlock,
+			},
+		},
+		{
+			name: "repeat decay",
+			option: ScoreOption{
+				RepeatDecay: 0.5,
+			},
+			text: "傻逼傻逼傻逼",
+			result: &Score{
+				Total:      3.5,
+				Categories: map[string]float64{"abuse": 3.5},
+				Verdict:    VerdictPass,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+
+			filter.AddEntries(entries...)
+
+			result := NewScorer(filter, tt.option).Score(tt.text)
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("Score() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
+
+func Test_ScorePhonetic(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "fuck", Weight: 10},
+		{Word: "shit", Weight: 10},
+	}
+	text := "phuck sheit"
+
+	tests := []struct {
+		name   string
+		option ScoreOption
+		result *Score
+	}{
+		{
+			name: "exact only",
+			option: ScoreOption{
+				ReviewThreshold: 3,
+				BlockThreshold:  8,
+			},
+			result: &Score{
+				Total:      0,
+				Categories: map[string]float64{},
+				Verdict:    VerdictPass,
+			},
+		},
+		{
+			name: "min confidence",
+			option: ScoreOption{
+				ReviewThreshold: 3,
+				BlockThreshold:  8,
+				MinConfidence:   PhoneticConfidence,
+			},
+			result: &Score{
+				Total:      8,
+				Categories: map[string]float64{},
+				Verdict:    VerdictBlock,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+
+			filter.SetPhonetic(true)
+			filter.AddEntries(entries...)
+
+			result := NewScorer(filter, tt.option).Score(text)
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("Score() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSessionTail = 32 // 无法得到过滤器中最长敏感词的长度时保留的文字数量
+	DefaultSessionTTL  = 10 * time.Minute
+)
+
+// tailer 返回匹配跨块或跨消息的敏感词需要保留的文字数量, 由最长的敏感词与最大的间隔得到, 随字典的更新变化
+type tailer interface {
+	tailLen() int
+}
+
+// SessionOption 会话选项
+type SessionOption struct {
+	Tail        int           // 保留的之前消息末尾的文字数量, 应不小于最长敏感词的长度减一, 为 0 时由过滤器中最长的敏感词得到, 见 NewSession
+	TTL         time.Duration // 会话多久没有新消息后过期, 为 0 时使用 DefaultSessionTTL
+	MaxSessions int           // 最多保留的会话数量, 超出时淘汰最久没有新消息的会话, 为 0 时不限制
+}
+
+// MessagePart 敏感词在一条消息中的部分, Start 与 End 为该消息中的文字(rune)下标区间
+type MessagePart struct {
+	ID    string
+	Start int
+	End   int
+}
+
+// SessionMatch 会话中匹配到的敏感词, Start 与 End 为相对当前消息的区间, 敏感词从之前的消息开始时 Start 为负数
+// Parts 按顺序列出敏感词涉及的每条消息
+type SessionMatch struct {
+	Match
+	Parts []MessagePart
+}
+
+// Session 会话扫描器, 查找被拆分到连续多条消息中的敏感词, 如 "敏" / "感词"
+// 每条新消息与之前消息末尾保留的文字拼接后重新扫描, 只返回结束于新消息中的敏感词
+// 跨消息的敏感词在之前消息中的部分不超过最长敏感词的长度减一, 因此只需要保留末尾的 Tail 个文字, 占用的内存有上限
+type Session struct {
+	filter Filter
+	tail   int
+
+	mu      sync.Mutex
+	runes   []rune
+	origins []MessagePart // 每个保留的文字所在的消息与下标
+	updated time.Time
+}
+
+// NewSession tail 为 0 时每条消息按过滤器当前最长的敏感词与最大的间隔决定保留的文字数量, 字典更新后自动调整
+// 过滤器不是本包的模型时使用 DefaultSessionTail, 规范化器会合并文字(如半角假名的浊音符号)时原文可能更长, 需要手动设置更大的 tail
+func NewSession(filter Filter, tail int) *Session {
+	if tail < 0 {
+		tail = 0
+	}
+
+	return &Session{
+		filter: filter,
+		tail:   tail,
+	}
+}
+
+// Feed 扫描一条新消息, 返回结束于这条消息中的敏感词, 包括从之前的消息开始的敏感词
+func (s *Session) Feed(id, message string, categories ...string) []SessionMatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []SessionMatch
+
+	runes := []rune(message)
+	offset := len(s.runes)
+
+	buf := append(s.runes, runes...)
+	origins := s.origins
+	for i := range runes {
+		origins = append(origins, MessagePart{ID: id, Start: i, End: i + 1})
+	}
+
+	for _, match := range s.filter.FindMatches(string(buf), categories...) {
+		if match.End <= offset {
+			continue
+		}
+
+		hit := SessionMatch{
+			Match: match,
+			Parts: messageParts(origins[match.Start:match.End]),
+		}
+		hit.Start -= offset
+		hit.End -= offset
+
+		res = append(res, hit)
+	}
+
+	if tail := s.tailLen(); len(buf) > tail {
+		buf, origins = buf[len(buf)-tail:], origins[len(origins)-tail:]
+	}
+	s.runes = append([]rune(nil), buf...)
+	s.origins = append([]MessagePart(nil), origins...)
+
+	return res
+}
+
+// tailLen 返回保留的文字数量, 见 NewSession
+func (s *Session) tailLen() int {
+	if s.tail > 0 {
+		return s.tail
+	}
+	if t, ok := s.filter.(tailer); ok {
+		return t.tailLen()
+	}
+
+	return DefaultSessionTail
+}
+
+// Reset 清空之前的消息
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runes, s.origins = nil, nil
+}
+
+// messageParts 将每个文字所在的消息合并为每条消息中的区间
+func messageParts(origins []MessagePart) []MessagePart {
+	var res []MessagePart
+
+	for _, origin := range origins {
+		if last := len(res) - 1; last >= 0 && res[last].ID == origin.ID && res[last].End == origin.Start {
+			res[last].End = origin.End
+			continue
+		}
+		res = append(res, origin)
+	}
+
+	return res
+}
+
+// Sessions 按会话(如聊天室或用户)保存 Session, 淘汰过期或超出数量的会话
+type Sessions struct {
+	filter Filter
+	option SessionOption
+	now    func() time.Time
+
+	mu       sync.Mutex
+	lru      *list.List // 最近有新消息的会话在前
+	sessions map[string]*list.Element
+}
+
+type sessionEntry struct {
+	key     string
+	session *Session
+}
+
+func NewSessions(filter Filter, option SessionOption) *Sessions {
+	if option.TTL <= 0 {
+		option.TTL = DefaultSessionTTL
+	}
+
+	return &Sessions{
+		filter:   filter,
+		option:   option,
+		now:      time.Now,
+		lru:      list.New(),
+		sessions: make(map[string]*list.Element),
+	}
+}
+
+// Feed 扫描会话 key 中的一条新消息, 见 Session.Feed
+func (s *Sessions) Feed(key, id, message string, categories ...string) []SessionMatch {
+	return s.Get(key).Feed(id, message, categories...)
+}
+
+// Get 返回会话 key 的 Session, 不存在或已过期时创建新的 Session
+func (s *Sessions) Get(key string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evict(now)
+
+	if elem, ok := s.sessions[key]; ok {
+		s.lru.MoveToFront(elem)
+		session := elem.Value.(*sessionEntry).session
+		session.updated = now
+		return session
+	}
+
+	session := NewSession(s.filter, s.option.Tail)
+	session.updated = now
+	s.sessions[key] = s.lru.PushFront(&sessionEntry{key: key, session: session})
+
+	if s.option.MaxSessions > 0 && s.lru.Len() > s.option.MaxSessions {
+		s.remove(s.lru.Back())
+	}
+
+	return session
+}
+
+// Delete 删除会话 key
+func (s *Sessions) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.sessions[key]; ok {
+		s.remove(elem)
+	}
+}
+
+// Len 返回未过期的会话数量
+func (s *Sessions) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evict(s.now())
+
+	return s.lru.Len()
+}
+
+// evict 从最久没有新消息的会话开始删除过期的会话
+func (s *Sessions) evict(now time.Time) {
+	for elem := s.lru.Back(); elem != nil; elem = s.lru.Back() {
+		if now.Sub(elem.Value.(*sessionEntry).session.updated) < s.option.TTL {
+			return
+		}
+		s.remove(elem)
+	}
+}
+
+func (s *Sessions) remove(elem *list.Element) {
+	delete(s.sessions, elem.Value.(*sessionEntry).key)
+	s.lru.Remove(elem)
+}
@@ -0,0 +1,127 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Session(t *testing.T) {
+	type message struct {
+		id   string
+		text string
+	}
+
+	tests := []struct {
+		name     string
+		messages []message
+		result   []SessionMatch
+	}{
+		{
+			name:     "split",
+			messages: []message{{"1", "你好敏"}, {"2", "感"}, {"3", "词1呀"}},
+			result: []SessionMatch{
+				{
+					Match: Match{Word: "敏感词1", Weight: 1, Text: "敏感词1", Start: -2, End: 2, Confidence: 1},
+					Parts: []MessagePart{{ID: "1", Start: 2, End: 3}, {ID: "2", Start: 0, End: 1}, {ID: "3", Start: 0, End: 2}},
+				},
+			},
+		},
+		{
+			name:     "within message",
+			messages: []message{{"1", "敏感词2"}, {"2", "敏感词3"}},
+			result: []SessionMatch{
+				{
+					Match: Match{Word: "敏感词2", Weight: 1, Text: "敏感词2", Start: 0, End: 4, Confidence: 1},
+					Parts: []MessagePart{{ID: "1", Start: 0, End: 4}},
+				},
+				{
+					Match: Match{Word: "敏感词3", Weight: 1, Text: "敏感词3", Start: 0, End: 4, Confidence: 1},
+					Parts: []MessagePart{{ID: "2", Start: 0, End: 4}},
+				},
+			},
+		},
+		{
+			name:     "tail",
+			messages: []message{{"1", "敏感"}, {"2", "这条消息足够长, 超过了保留的文字数量"}, {"3", "词1"}},
+			result:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+			filter.AddWords(words1...)
+
+			session := NewSession(filter, 8)
+
+			var result []SessionMatch
+			for _, message := range tt.messages {
+				result = append(result, session.Feed(message.id, message.text)...)
+			}
+
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("Feed() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
+
+func Test_SessionDefaultTail(t *testing.T) {
+	word := strings.Repeat("敏", 40) + "感"
+
+	for _, filter := range []interface {
+		testFilter
+		AddWords(words ...string)
+	}{NewDfaModel(), NewAcModel(), NewFilterDat()} {
+		session := NewSession(filter, 0)
+		filter.AddWords(word)
+
+		session.Feed("1", "你好"+strings.Repeat("敏", 40))
+		result := session.Feed("2", "感")
+
+		want := []SessionMatch{
+			{
+				Match: Match{Word: word, Weight: 1, Text: word, Start: -40, End: 1, Confidence: 1},
+				Parts: []MessagePart{{ID: "1", Start: 2, End: 42}, {ID: "2", Start: 0, End: 1}},
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("Feed() = %v, want %v", result, want)
+		}
+	}
+}
+
+func Test_Sessions(t *testing.T) {
+	filter := NewAcModel()
+	filter.AddWords(words1...)
+
+	now := time.Now()
+	sessions := NewSessions(filter, SessionOption{TTL: time.Minute, MaxSessions: 2})
+	sessions.now = func() time.Time {
+		return now
+	}
+
+	sessions.Feed("a", "1", "敏感")
+	sessions.Feed("b", "1", "敏感")
+
+	if result := sessions.Feed("a", "2", "词1"); len(result) != 1 {
+		t.Errorf("Feed() = %v, want 1 match", result)
+	}
+
+	// 超出数量时淘汰最久没有新消息的会话 b
+	sessions.Feed("c", "1", "敏感")
+	if result := sessions.Feed("b", "2", "词1"); len(result) != 0 {
+		t.Errorf("Feed() after eviction = %v, want no match", result)
+	}
+
+	// 过期的会话被删除
+	now = now.Add(2 * time.Minute)
+	if n := sessions.Len(); n != 0 {
+		t.Errorf("Len() = %v, want 0", n)
+	}
+	if result := sessions.Feed("c", "2", "词1"); len(result) != 0 {
+		t.Errorf("Feed() after ttl = %v, want no match", result)
+	}
+}
@@ -0,0 +1,175 @@
+package filter
+
+import (
+	"sort"
+	"unicode"
+)
+
+const (
+	ellipsis        = "…"
+	zeroWidthJoiner = '‍'
+)
+
+// Snippet 敏感词附近的上下文片段, Start 与 End 为片段在原文中的区间
+// 片段没有到达原文开头或结尾时, Text 的前后会加上省略号
+type Snippet struct {
+	Text    string
+	Start   int
+	End     int
+	Matches []Match
+}
+
+// SnippetExtractor 提取每个敏感词前后 radius 个文字作为上下文, 用于人工审核
+// 上下文相互重叠的敏感词合并为一个片段, 片段的边界不会拆开组合字符, emoji 序列, 国旗与韩文字母组成的音节
+type SnippetExtractor struct {
+	filter Filter
+	radius int
+}
+
+func NewSnippetExtractor(filter Filter, radius int) *SnippetExtractor {
+	return &SnippetExtractor{
+		filter: filter,
+		radius: radius,
+	}
+}
+
+func (e *SnippetExtractor) Extract(text string, categories ...string) []Snippet {
+	var res []Snippet
+
+	runes := []rune(text)
+	found := e.filter.FindMatches(text, categories...)
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Start < found[j].Start
+	})
+
+	for _, match := range found {
+		start, end := match.Start-e.radius, match.End+e.radius
+		if start < 0 {
+			start = 0
+		}
+		if end > len(runes) {
+			end = len(runes)
+		}
+		start, end = graphemeStart(runes, start), graphemeEnd(runes, end)
+
+		if last := len(res) - 1; last >= 0 && start <= res[last].End {
+			if end > res[last].End {
+				res[last].End = end
+			}
+			res[last].Matches = append(res[last].Matches, match)
+			continue
+		}
+
+		res = append(res, Snippet{
+			Start:   start,
+			End:     end,
+			Matches: []Match{match},
+		})
+	}
+
+	for i := range res {
+		res[i].Text = string(runes[res[i].Start:res[i].End])
+		if res[i].Start > 0 {
+			res[i].Text = ellipsis + res[i].Text
+		}
+		if res[i].End < len(runes) {
+			res[i].Text += ellipsis
+		}
+	}
+
+	return res
+}
+
+// graphemeStart 将片段起点向前移动到字素簇的开头
+func graphemeStart(runes []rune, start int) int {
+	for start > 0 && start < len(runes) && !isGraphemeBreak(runes, start) {
+		start--
+	}
+
+	return start
+}
+
+// graphemeEnd 将片段终点向后移动到字素簇的结尾
+func graphemeEnd(runes []rune, end int) int {
+	for end > 0 && end < len(runes) && !isGraphemeBreak(runes, end) {
+		end++
+	}
+
+	return end
+}
+
+// isGraphemeBreak runes[i-1] 与 runes[i] 之间是否为字素簇边界
+// 处理附着字符与零宽连接符, 组成国旗的一对区域指示符, 以及组成一个音节的韩文初声/中声/终声字母
+func isGraphemeBreak(runes []rune, i int) bool {
+	prev, r := runes[i-1], runes[i]
+
+	if isGraphemeExtend(r) || prev == zeroWidthJoiner {
+		return false
+	}
+
+	if isRegionalIndicator(prev) && isRegionalIndicator(r) {
+		// 区域指示符从前往后两两组成国旗
+		n := 0
+		for j := i - 1; j >= 0 && isRegionalIndicator(runes[j]); j-- {
+			n++
+		}
+		return n%2 == 0
+	}
+
+	switch prevType, nextType := hangulJamoType(prev), hangulJamoType(r); prevType {
+	case jamoLeading:
+		return nextType == jamoNone
+	case jamoVowel, jamoSyllableLV:
+		return nextType != jamoVowel && nextType != jamoTrailing
+	case jamoTrailing, jamoSyllableLVT:
+		return nextType != jamoTrailing
+	}
+
+	return true
+}
+
+// isGraphemeExtend 是否为附着在前一个文字上的字符(组合符号, 变体选择符, 肤色修饰符, 零宽连接符)
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc),
+		r == zeroWidthJoiner,
+		r >= 0xFE00 && r <= 0xFE0F,   // 变体选择符
+		r >= 0x1F3FB && r <= 0x1F3FF: // 肤色修饰符
+		return true
+	}
+
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// 韩文字母在字素簇中的类型
+const (
+	jamoNone        = iota
+	jamoLeading     // 初声 L
+	jamoVowel       // 中声 V
+	jamoTrailing    // 终声 T
+	jamoSyllableLV  // 没有终声的音节
+	jamoSyllableLVT // 有终声的音节
+)
+
+func hangulJamoType(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return jamoLeading
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return jamoVowel
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return jamoTrailing
+	case r >= hangulSyllableBase && r <= hangulSyllableLast:
+		if (r-hangulSyllableBase)%hangulFinalCount == 0 {
+			return jamoSyllableLV
+		}
+		return jamoSyllableLVT
+	}
+
+	return jamoNone
+}
@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SnippetExtractor(t *testing.T) {
+	type args struct {
+		text   string
+		radius int
+	}
+
+	tests := []struct {
+		name   string
+		args   args
+		result []string
+	}{
+		{
+			name: "ellipsis",
+			args: args{
+				text:   "这是一段很长的文本,中间有敏感词1,后面还有很多内容",
+				radius: 3,
+			},
+			result: []string{"…中间有敏感词1,后面…"},
+		},
+		{
+			name: "merge",
+			args: args{
+				text:   "开头敏感词1和敏感词2,后面还有很多内容,最后是敏感词3",
+				radius: 2,
+			},
+			result: []string{"开头敏感词1和敏感词2,后…", "…后是敏感词3"},
+		},
+		{
+			name: "grapheme",
+			args: args{
+				text:   "x👍🏽敏感词1e\u0301y",
+				radius: 1,
+			},
+			result: []string{"…👍🏽敏感词1e\u0301…"},
+		},
+		{
+			name: "flag",
+			args: args{
+				text:   "x🇨🇳🇺🇸敏感词1🇯🇵🇰🇷",
+				radius: 1,
+			},
+			result: []string{"…🇺🇸敏感词1🇯🇵…"},
+		},
+		{
+			name: "hangul jamo",
+			args: args{
+				text:   "x\u1100\u1161\u11A8敏感词1\u1100\u1161y",
+				radius: 1,
+			},
+			result: []string{"…\u1100\u1161\u11A8敏感词1\u1100\u1161…"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewAcModel()
+
+			filter.AddWords(words1...)
+
+			var result []string
+			for _, snippet := range NewSnippetExtractor(filter, tt.args.radius).Extract(tt.args.text) {
+				result = append(result, snippet.Text)
+			}
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("Extract() = %v, want %v", result, tt.result)
+			}
+		})
+	}
+}
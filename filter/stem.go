@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+// inflections 返回设置了 dict.Entry.Stem 的英文敏感词的常见屈折变化, 如 "kill" -> "kills", "killed", "killing"
+// 只处理拉丁字母组成的敏感词, 规则较为宽松, 生成的不存在的词形不会影响匹配结果
+func inflections(entry *dict.Entry) []string {
+	if entry == nil || !entry.Stem || !isLatinWord(entry.Word) {
+		return nil
+	}
+
+	var res []string
+	set := map[string]struct{}{entry.Word: {}}
+
+	add := func(forms ...string) {
+		for _, form := range forms {
+			if _, ok := set[form]; !ok {
+				set[form] = struct{}{}
+				res = append(res, form)
+			}
+		}
+	}
+
+	word := entry.Word
+	runes := []rune(word)
+	last := runes[len(runes)-1]
+	stem := string(runes[:len(runes)-1])
+
+	// 复数与第三人称单数
+	switch {
+	case hasSuffix(word, "s", "x", "z", "ch", "sh"):
+		add(word + "es")
+	case last == 'y' && !isVowel(runes, len(runes)-2):
+		add(stem + "ies")
+	default:
+		add(word + "s")
+	}
+
+	// 过去式, 过去分词与现在分词
+	switch {
+	case hasSuffix(word, "ie"):
+		add(word+"d", string(runes[:len(runes)-2])+"ying")
+	case hasSuffix(word, "ee", "ye", "oe"):
+		add(word+"d", word+"ing")
+	case last == 'e':
+		add(word+"d", stem+"ing")
+	case last == 'y' && !isVowel(runes, len(runes)-2):
+		add(stem+"ied", word+"ing")
+	default:
+		add(word+"ed", word+"ing")
+
+		// 以辅音-元音-辅音结尾时通常双写最后一个辅音, 如 "stab" -> "stabbed"
+		if isConsonant(runes, len(runes)-1) && isVowel(runes, len(runes)-2) && isConsonant(runes, len(runes)-3) &&
+			!strings.ContainsRune("wxy", last) {
+			add(word+string(last)+"ed", word+string(last)+"ing")
+		}
+	}
+
+	return res
+}
+
+func isLatinWord(word string) bool {
+	if word == "" {
+		return false
+	}
+
+	for _, r := range word {
+		if !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasSuffix(word string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(strings.ToLower(word), suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isVowel(runes []rune, i int) bool {
+	return i >= 0 && strings.ContainsRune("aeiouAEIOU", runes[i])
+}
+
+func isConsonant(runes []rune, i int) bool {
+	return i >= 0 && !isVowel(runes, i)
+}
+
+// isWordBoundary 区间 [start, end) 前后是否都不是字母或数字, 用于避免词形扩展的敏感词匹配到更长的单词中, 如 "kill" 与 "skill"
+func isWordBoundary(runes []rune, start, end int) bool {
+	return (start == 0 || !isWordRune(runes[start-1])) && (end == len(runes) || !isWordRune(runes[end]))
+}
+
+// isWordRune 只有拉丁字母与数字组成单词, 中文等文字前后不需要分隔
+func isWordRune(r rune) bool {
+	return unicode.Is(unicode.Latin, r) || unicode.IsDigit(r)
+}
+
+// bounded 忽略不在单词边界上的词形扩展敏感词
+func bounded(runes []rune, fn scanFunc) scanFunc {
+	return func(start, end int, entry *dict.Entry, confidence float64) bool {
+		if entry.Stem && !isWordBoundary(runes, start, end) {
+			return true
+		}
+
+		return fn(start, end, entry, confidence)
+	}
+}
@@ -0,0 +1,64 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+type stemFilter interface {
+	testFilter
+	DelWords(words ...string)
+}
+
+func Test_Stem(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "kill", Stem: true},
+		{Word: "stab", Stem: true},
+		{Word: "die", Stem: true},
+		{Word: "kills", Categories: []string{"explicit"}},
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		del    []string
+		result []string
+	}{
+		{
+			name:   "inflections",
+			text:   "kill, kills, killed, killing, stabbed, dying",
+			result: []string{"kill:kill", "kills:kills", "kill:killed", "kill:killing", "stab:stabbed", "die:dying"},
+		},
+		{
+			name:   "boundary",
+			text:   "skill, killer, 2kill, 我要kill你",
+			result: []string{"kill:kill"},
+		},
+		{
+			name:   "delete",
+			text:   "kill, kills, killed",
+			del:    []string{"kill"},
+			result: []string{"kills:kills"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, filter := range []stemFilter{NewDfaModel(), NewAcModel()} {
+				filter.AddEntries(entries...)
+				filter.DelWords(tt.del...)
+
+				var result []string
+				for _, match := range filter.FindMatches(tt.text) {
+					result = append(result, match.Word+":"+match.Text)
+				}
+
+				if !reflect.DeepEqual(result, tt.result) {
+					t.Errorf("FindMatches() = %v, want %v", result, tt.result)
+				}
+			}
+		})
+	}
+}
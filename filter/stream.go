@@ -0,0 +1,206 @@
+package filter
+
+import (
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+// streamChunk ScanReader 每次读取的字节数
+const streamChunk = 32 * 1024
+
+// streamTail 流式匹配时需要保留的文字数量, 比最长的敏感词多保留一个文字用于判断单词边界
+// 允许间隔时还需要保留敏感词每两个文字之间的最大间隔
+func streamTail(longest, maxGap int) int {
+	tail := longest + 1
+	if maxGap > 0 && longest > 1 {
+		tail += (longest - 1) * maxGap
+	}
+
+	return tail
+}
+
+// stream 按块解码并匹配文本, 只保留末尾 tail 个规范化后的文字用于匹配跨块的敏感词
+// 起始位置在保留的文字之前的敏感词已经完整出现在缓冲区中, 可以确定匹配结果
+type stream struct {
+	scanner     scanner
+	normalizers normalizers
+	tail        int
+	categories  []string
+
+	pending []byte // 末尾不完整的 UTF-8 编码, 等待下一块数据
+	runes   []rune
+	offset  int // runes[0] 在全文中的文字下标
+	done    int // runes 中已经处理过的文字数量, 这些文字只作为判断单词边界的上下文保留
+}
+
+func newStream(s scanner, n normalizers, tail int, categories []string) *stream {
+	return &stream{
+		scanner:     s,
+		normalizers: n,
+		tail:        tail,
+		categories:  categories,
+	}
+}
+
+// feed 解码 p 并处理可以确定结果的文字, eof 为 true 时处理剩余的所有文字
+// fn 接收本次处理的文字及其在全文中的起始下标, 以及起始位置在这些文字中的敏感词(按起始位置升序)
+func (s *stream) feed(p []byte, eof bool, fn func(runes []rune, offset int, found []Match) error) error {
+	s.decode(p, eof)
+
+	normalized, spans := s.normalizers.normalize(s.runes)
+
+	flush := len(s.runes)
+	if !eof {
+		flush = s.done
+		if keep := len(normalized) - s.tail; keep > 0 {
+			flush, _ = origin(spans, keep, keep+1)
+		}
+		if flush < s.done {
+			flush = s.done
+		}
+	}
+
+	var found []Match
+
+	s.scanner.scan(normalized, aligned(spans, func(start, end int, entry *dict.Entry, confidence float64) bool {
+		if !entry.HasCategory(s.categories...) {
+			return true
+		}
+
+		start, end = origin(spans, start, end)
+		if start < s.done || start >= flush {
+			return true
+		}
+
+		match := newMatch(s.runes, entry, start, end, confidence)
+		match.Start += s.offset
+		match.End += s.offset
+		found = append(found, match)
+
+		return true
+	}))
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Start < found[j].Start
+	})
+
+	if err := fn(s.runes[s.done:flush], s.offset+s.done, found); err != nil {
+		return err
+	}
+
+	// 丢弃处理过的文字, 保留一个文字用于判断单词边界
+	drop := flush - 1
+	if drop < 0 {
+		drop = 0
+	}
+	s.runes = append(s.runes[:0], s.runes[drop:]...)
+	s.offset += drop
+	s.done = flush - drop
+
+	return nil
+}
+
+// decode 解码完整的 UTF-8 编码, 无效的字节与 []rune(string) 一样解码为 utf8.RuneError
+func (s *stream) decode(p []byte, eof bool) {
+	buf := append(s.pending, p...)
+
+	for len(buf) > 0 {
+		if !eof && !utf8.FullRune(buf) {
+			break
+		}
+
+		r, size := utf8.DecodeRune(buf)
+		s.runes = append(s.runes, r)
+		buf = buf[size:]
+	}
+
+	s.pending = append(s.pending[:0], buf...)
+}
+
+// scanReader 按块读取 reader 并查找敏感词, 匹配结果的下标为全文中的文字下标
+func scanReader(s scanner, n normalizers, tail int, reader io.Reader, fn func(Match), categories []string) error {
+	st := newStream(s, n, tail, categories)
+	report := func(_ []rune, _ int, found []Match) error {
+		for _, match := range found {
+			fn(match)
+		}
+		return nil
+	}
+
+	buf := make([]byte, streamChunk)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			_ = st.feed(buf[:n], false, report)
+		}
+
+		if err == io.EOF {
+			return st.feed(nil, true, report)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReplacingWriter 和谐写入的文本中的敏感词后写入下层的 Writer, 只保留最长敏感词长度的文字用于匹配跨块的敏感词
+// 结果与对全文调用 Replace 相同, 写入结束后需要调用 Close 写入保留的文字, Close 不会关闭下层的 Writer
+type ReplacingWriter struct {
+	w      io.Writer
+	repl   rune
+	stream *stream
+	maxEnd int // 已经匹配到的敏感词在全文中的最大结束位置
+	buf    []byte
+}
+
+func newReplacingWriter(s scanner, n normalizers, tail int, w io.Writer, repl rune, categories []string) *ReplacingWriter {
+	return &ReplacingWriter{
+		w:      w,
+		repl:   repl,
+		stream: newStream(exactScanner{s}, n, tail, categories),
+	}
+}
+
+func (w *ReplacingWriter) Write(p []byte) (int, error) {
+	if err := w.stream.feed(p, false, w.emit); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close 和谐并写入保留的文字
+func (w *ReplacingWriter) Close() error {
+	return w.stream.feed(nil, true, w.emit)
+}
+
+// emit 写入处理过的文字, 被敏感词覆盖的文字替换为 repl
+func (w *ReplacingWriter) emit(runes []rune, offset int, found []Match) error {
+	if len(runes) == 0 {
+		return nil
+	}
+
+	w.buf = w.buf[:0]
+
+	for i, r := range runes {
+		pos := offset + i
+		for len(found) > 0 && found[0].Start <= pos {
+			if found[0].End > w.maxEnd {
+				w.maxEnd = found[0].End
+			}
+			found = found[1:]
+		}
+
+		if pos < w.maxEnd {
+			r = w.repl
+		}
+		w.buf = utf8.AppendRune(w.buf, r)
+	}
+
+	_, err := w.w.Write(w.buf)
+
+	return err
+}
@@ -0,0 +1,94 @@
+package filter
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
+
+func Test_Stream(t *testing.T) {
+	entries := []dict.Entry{
+		{Word: "敏感词1"}, {Word: "敏感词2"}, {Word: "感词"}, {Word: "kill", Stem: true},
+	}
+
+	filters := map[string]func() testFilter{
+		"dfa": func() testFilter { return NewDfaModel() },
+		"ac":  func() testFilter { return NewAcModel() },
+	}
+
+	texts := []string{
+		"",
+		"没有敏感词",
+		"这是敏感词1, 也是敏感词2",
+		"敏感词1敏感词2敏感词1",
+		"he kills, skills and killing",
+		"\xe6\x95敏感词1\xff",
+	}
+
+	for name, newFilter := range filters {
+		filter := newFilter()
+		filter.AddEntries(entries...)
+
+		for _, text := range texts {
+			want := filter.FindMatches(text)
+			sort.SliceStable(want, func(i, j int) bool {
+				return want[i].Start < want[j].Start
+			})
+
+			for chunk := 1; chunk <= len(text)+1; chunk++ {
+				var got []Match
+				if err := filter.ScanReader(&chunkReader{text: text, size: chunk}, func(match Match) {
+					got = append(got, match)
+				}); err != nil {
+					t.Fatalf("%s: ScanReader() error = %v", name, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: ScanReader(%q, %d) = %v, want %v", name, text, chunk, got, want)
+				}
+
+				var builder strings.Builder
+				writer := filter.NewReplacingWriter(&builder, '*')
+				for i := 0; i < len(text); i += chunk {
+					end := i + chunk
+					if end > len(text) {
+						end = len(text)
+					}
+					_, _ = writer.Write([]byte(text[i:end]))
+				}
+				_ = writer.Close()
+				if res := filter.Replace(text, '*'); builder.String() != res {
+					t.Errorf("%s: ReplacingWriter(%q, %d) = %q, want %q", name, text, chunk, builder.String(), res)
+				}
+			}
+		}
+	}
+}
+
+// chunkReader 每次最多返回 size 个字节
+type chunkReader struct {
+	text string
+	size int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.text == "" {
+		return 0, io.EOF
+	}
+
+	n := r.size
+	if n > len(p) {
+		n = len(p)
+	}
+	if n > len(r.text) {
+		n = len(r.text)
+	}
+
+	n = copy(p, r.text[:n])
+	r.text = r.text[n:]
+
+	return n, nil
+}
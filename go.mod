@@ -9,6 +9,7 @@ require (
 	github.com/orcaman/concurrent-map/v2 v2.0.1
 	github.com/sgoware/ds v0.0.0-20230824044855-a6df320656ac
 	go.mongodb.org/mongo-driver v1.11.1
+	golang.org/x/text v0.3.7
 )
 
 require (
@@ -37,7 +38,6 @@ require (
 	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2 // indirect
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c // indirect
 	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
-	golang.org/x/text v0.3.7 // indirect
 	golang.org/x/tools v0.1.11 // indirect
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
 )
@@ -8,6 +8,7 @@ import (
 type Manager struct {
 	store.Store
 	filter.Filter
+	Rules *filter.RuleMatcher // 从数据源加载的布尔规则
 }
 
 func NewFilter(storeOption StoreOption, filterOption FilterOption) *Manager {
@@ -23,23 +24,41 @@ func NewFilter(storeOption StoreOption, filterOption FilterOption) *Manager {
 
 	switch filterOption.Type {
 	case FilterDfa:
-		dfaModel := filter.NewDfaModel()
+		dfaModel := filter.NewDfaModel(filterOption.Normalizers...)
+		dfaModel.SetGap(filterOption.Gap)
+		dfaModel.SetPhonetic(filterOption.Phonetic)
 
 		go dfaModel.Listen(filterStore.GetAddChan(), filterStore.GetDelChan())
 
 		myFilter = dfaModel
 	case FilterAc:
-		acModel := filter.NewAcModel()
+		acModel := filter.NewAcModel(filterOption.Normalizers...)
+		acModel.SetGap(filterOption.Gap)
+		acModel.SetPhonetic(filterOption.Phonetic)
 
 		go acModel.Listen(filterStore.GetAddChan(), filterStore.GetDelChan())
 
 		myFilter = acModel
+	case FilterDat:
+		datModel := filter.NewFilterDat(filterOption.Normalizers...)
+		datModel.SetGap(filterOption.Gap)
+		datModel.SetPhonetic(filterOption.Phonetic)
+
+		go datModel.Listen(filterStore.GetAddChan(), filterStore.GetDelChan())
+
+		myFilter = datModel
 	default:
 		panic("invalid filter type")
 	}
 
+	ruleMatcher := filter.NewRuleMatcher(myFilter)
+	ruleMatcher.SetNormalizers(filterOption.Normalizers...)
+
+	go ruleMatcher.Listen(filterStore.GetRuleAddChan(), filterStore.GetRuleDelChan())
+
 	return &Manager{
 		Store:  filterStore,
 		Filter: myFilter,
+		Rules:  ruleMatcher,
 	}
 }
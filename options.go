@@ -1,6 +1,7 @@
 package sensitive
 
 import (
+	"github.com/sgoware/go-sensitive/filter"
 	"github.com/sgoware/go-sensitive/store"
 )
 
@@ -13,6 +14,7 @@ const (
 const (
 	FilterDfa = iota
 	FilterAc
+	FilterDat
 )
 
 type StoreOption struct {
@@ -22,5 +24,8 @@ type StoreOption struct {
 }
 
 type FilterOption struct {
-	Type uint32
+	Type        uint32
+	Normalizers []filter.Normalizer
+	Gap         filter.GapOption // 敏感词相邻文字之间允许的间隔, 默认不允许
+	Phonetic    bool             // 是否启用英文语音匹配
 }
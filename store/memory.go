@@ -5,22 +5,31 @@ import (
 	"errors"
 	"github.com/imroc/req/v3"
 	cmap "github.com/orcaman/concurrent-map/v2"
+	"github.com/sgoware/go-sensitive/dict"
 	"io"
 	"net/http"
 	"os"
 )
 
 type MemoryModel struct {
-	store   cmap.ConcurrentMap[string, struct{}]
-	addChan chan string
+	store   cmap.ConcurrentMap[string, dict.Entry]
+	addChan chan dict.Entry
 	delChan chan string
+
+	rules       cmap.ConcurrentMap[string, dict.Rule]
+	ruleAddChan chan dict.Rule
+	ruleDelChan chan string
 }
 
 func NewMemoryModel() *MemoryModel {
 	return &MemoryModel{
-		store:   cmap.New[struct{}](),
-		addChan: make(chan string),
+		store:   cmap.New[dict.Entry](),
+		addChan: make(chan dict.Entry),
 		delChan: make(chan string),
+
+		rules:       cmap.New[dict.Rule](),
+		ruleAddChan: make(chan dict.Rule),
+		ruleDelChan: make(chan string),
 	}
 }
 
@@ -35,7 +44,7 @@ func (m *MemoryModel) LoadDictPath(paths ...string) error {
 				return err
 			}
 
-			return m.LoadDict(f)
+			return m.loadDict(f, path)
 		}(path)
 		if err != nil {
 			return err
@@ -63,7 +72,7 @@ func (m *MemoryModel) LoadDictHttp(urls ...string) error {
 				_ = Body.Close()
 			}(httpRes.Body)
 
-			return m.LoadDict(httpRes.Body)
+			return m.loadDict(httpRes.Body, url)
 		}(url)
 		if err != nil {
 			return err
@@ -74,6 +83,11 @@ func (m *MemoryModel) LoadDictHttp(urls ...string) error {
 }
 
 func (m *MemoryModel) LoadDict(reader io.Reader) error {
+	return m.loadDict(reader, "memory")
+}
+
+// loadDict 加载字典, source 记录敏感词的来源
+func (m *MemoryModel) loadDict(reader io.Reader, source string) error {
 	buf := bufio.NewReader(reader)
 	for {
 		line, _, err := buf.ReadLine()
@@ -84,8 +98,11 @@ func (m *MemoryModel) LoadDict(reader io.Reader) error {
 			break
 		}
 
-		m.store.Set(string(line), struct{}{})
-		m.addChan <- string(line)
+		entry := dict.ParseEntry(string(line))
+		entry.Source = source
+
+		m.store.Set(entry.Word, entry)
+		m.addChan <- entry
 	}
 
 	return nil
@@ -114,7 +131,17 @@ func (m *MemoryModel) ReadString() []string {
 	return res
 }
 
-func (m *MemoryModel) GetAddChan() <-chan string {
+func (m *MemoryModel) ReadEntries() []dict.Entry {
+	res := make([]dict.Entry, 0, m.store.Count())
+
+	for _, entry := range m.store.Items() {
+		res = append(res, entry)
+	}
+
+	return res
+}
+
+func (m *MemoryModel) GetAddChan() <-chan dict.Entry {
 	return m.addChan
 }
 
@@ -123,9 +150,23 @@ func (m *MemoryModel) GetDelChan() <-chan string {
 }
 
 func (m *MemoryModel) AddWord(words ...string) error {
+	entries := make([]dict.Entry, 0, len(words))
+
 	for _, word := range words {
-		m.store.Set(word, struct{}{})
-		m.addChan <- word
+		entries = append(entries, dict.Entry{Word: word})
+	}
+
+	return m.AddEntry(entries...)
+}
+
+func (m *MemoryModel) AddEntry(entries ...dict.Entry) error {
+	for _, entry := range entries {
+		if entry.Source == "" {
+			entry.Source = "memory"
+		}
+
+		m.store.Set(entry.Word, entry)
+		m.addChan <- entry
 	}
 
 	return nil
@@ -139,3 +180,69 @@ func (m *MemoryModel) DelWord(words ...string) error {
 
 	return nil
 }
+
+func (m *MemoryModel) LoadRulePath(paths ...string) error {
+	return loadRulePath(paths, m.loadRules)
+}
+
+func (m *MemoryModel) LoadRuleHttp(urls ...string) error {
+	return loadRuleHttp(urls, m.loadRules)
+}
+
+func (m *MemoryModel) LoadRules(reader io.Reader) error {
+	return m.loadRules(reader, "memory")
+}
+
+// loadRules 加载规则, source 记录规则的来源
+func (m *MemoryModel) loadRules(reader io.Reader, source string) error {
+	rules, err := readRules(reader, source)
+	if err != nil {
+		return err
+	}
+
+	return m.AddRule(rules...)
+}
+
+func (m *MemoryModel) ReadRules() []dict.Rule {
+	res := make([]dict.Rule, 0, m.rules.Count())
+
+	for _, rule := range m.rules.Items() {
+		res = append(res, rule)
+	}
+
+	return res
+}
+
+func (m *MemoryModel) GetRuleAddChan() <-chan dict.Rule {
+	return m.ruleAddChan
+}
+
+func (m *MemoryModel) GetRuleDelChan() <-chan string {
+	return m.ruleDelChan
+}
+
+func (m *MemoryModel) AddRule(rules ...dict.Rule) error {
+	if err := checkRules(rules); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if rule.Source == "" {
+			rule.Source = "memory"
+		}
+
+		m.rules.Set(rule.Name, rule)
+		m.ruleAddChan <- rule
+	}
+
+	return nil
+}
+
+func (m *MemoryModel) DelRule(names ...string) error {
+	for _, name := range names {
+		m.rules.Remove(name)
+		m.ruleDelChan <- name
+	}
+
+	return nil
+}
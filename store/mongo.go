@@ -10,36 +10,51 @@ import (
 	"os"
 
 	"github.com/imroc/req/v3"
+	"github.com/sgoware/go-sensitive/dict"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
-	defaultCollection = "dirties"
+	defaultCollection     = "dirties"
+	defaultRuleCollection = "dirty_rules"
 )
 
 type MongoConfig struct {
-	Address    string
-	Port       string
-	Username   string
-	Password   string
-	Database   string
-	Collection string
-	FieldName  string
+	Address        string
+	Port           string
+	Username       string
+	Password       string
+	Database       string
+	Collection     string
+	RuleCollection string
+	FieldName      string
 }
 
 type doc struct {
-	Id   string `bson:"_id"`
-	Word string `bson:"word"`
+	Id          string   `bson:"_id"`
+	Word        string   `bson:"word"`
+	Categories  []string `bson:"categories"`
+	Weight      float64  `bson:"weight"`
+	Replacement string   `bson:"replacement"`
+	Options     string   `bson:"options"`
+}
+
+type ruleDoc struct {
+	Name string `bson:"name"`
+	Expr string `bson:"expr"`
 }
 
 type MongoModel struct {
 	store     *mongo.Collection
+	rules     *mongo.Collection
 	fieldName string
 
-	addChan chan string
-	delChan chan string
+	addChan     chan dict.Entry
+	delChan     chan string
+	ruleAddChan chan dict.Rule
+	ruleDelChan chan string
 }
 
 func NewMongoModel(config *MongoConfig) *MongoModel {
@@ -87,12 +102,31 @@ func NewMongoModel(config *MongoConfig) *MongoModel {
 		return nil
 	}
 
+	if config.RuleCollection == "" {
+		config.RuleCollection = defaultRuleCollection
+	}
+
+	rules := mdb.Database(config.Database).Collection(config.RuleCollection)
+
+	_, err = rules.Indexes().CreateOne(context.Background(),
+		mongo.IndexModel{
+			Keys:    bson.D{bson.E{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		return nil
+	}
+
 	return &MongoModel{
 		store:     collection,
+		rules:     rules,
 		fieldName: config.FieldName,
 
-		addChan: make(chan string),
-		delChan: make(chan string),
+		addChan:     make(chan dict.Entry),
+		delChan:     make(chan string),
+		ruleAddChan: make(chan dict.Rule),
+		ruleDelChan: make(chan string),
 	}
 }
 
@@ -107,7 +141,7 @@ func (m *MongoModel) LoadDictPath(paths ...string) error {
 				return err
 			}
 
-			return m.LoadDict(f)
+			return m.loadDict(f, path)
 		}(path)
 		if err != nil {
 			return err
@@ -135,7 +169,7 @@ func (m *MongoModel) LoadDictHttp(urls ...string) error {
 				_ = Body.Close()
 			}(httpRes.Body)
 
-			return m.LoadDict(httpRes.Body)
+			return m.loadDict(httpRes.Body, url)
 		}(url)
 		if err != nil {
 			return err
@@ -146,8 +180,13 @@ func (m *MongoModel) LoadDictHttp(urls ...string) error {
 }
 
 func (m *MongoModel) LoadDict(reader io.Reader) error {
+	return m.loadDict(reader, m.source())
+}
+
+// loadDict 加载字典, source 记录敏感词的来源, 重复的敏感词只保留最后一条
+func (m *MongoModel) loadDict(reader io.Reader, source string) error {
 	buf := bufio.NewReader(reader)
-	var words []interface{}
+	var entries []dict.Entry
 
 	for {
 		line, _, err := buf.ReadLine()
@@ -158,13 +197,25 @@ func (m *MongoModel) LoadDict(reader io.Reader) error {
 			break
 		}
 
-		word := string(line)
+		entry := dict.ParseEntry(string(line))
+		entry.Source = source
+
+		entries = append(entries, entry)
+	}
+
+	entries = lastEntries(entries)
+	words := make([]interface{}, 0, len(entries))
 
+	for _, entry := range entries {
 		words = append(words, bson.D{
-			{m.fieldName, word},
+			bson.E{Key: m.fieldName, Value: entry.Word},
+			bson.E{Key: "categories", Value: entry.Categories},
+			bson.E{Key: "weight", Value: entry.Weight},
+			bson.E{Key: "replacement", Value: entry.Replacement},
+			bson.E{Key: "options", Value: entry.FormatOptions()},
 		})
 
-		m.addChan <- word
+		m.addChan <- entry
 	}
 
 	ctx := context.Background()
@@ -231,7 +282,51 @@ func (m *MongoModel) ReadString() []string {
 	return res
 }
 
-func (m *MongoModel) GetAddChan() <-chan string {
+func (m *MongoModel) ReadEntries() []dict.Entry {
+	ctx := context.Background()
+	cur, err := m.store.Find(ctx,
+		bson.D{},
+		options.Find().SetProjection(
+			bson.D{
+				bson.E{Key: "_id", Value: 0},
+				bson.E{Key: "word", Value: 1},
+				bson.E{Key: "categories", Value: 1},
+				bson.E{Key: "weight", Value: 1},
+				bson.E{Key: "replacement", Value: 1},
+				bson.E{Key: "options", Value: 1},
+			},
+		),
+	)
+	if err != nil {
+		return nil
+	}
+
+	var words []*doc
+
+	err = cur.All(ctx, &words)
+	if err != nil {
+		return nil
+	}
+
+	res := make([]dict.Entry, 0, len(words))
+
+	for _, word := range words {
+		entry := dict.Entry{
+			Word:        word.Word,
+			Categories:  word.Categories,
+			Weight:      word.Weight,
+			Replacement: word.Replacement,
+			Source:      m.source(),
+		}
+		entry.ParseOptions(word.Options)
+
+		res = append(res, entry)
+	}
+
+	return res
+}
+
+func (m *MongoModel) GetAddChan() <-chan dict.Entry {
 	return m.addChan
 }
 
@@ -240,14 +335,28 @@ func (m *MongoModel) GetDelChan() <-chan string {
 }
 
 func (m *MongoModel) AddWord(words ...string) error {
+	entries := make([]dict.Entry, 0, len(words))
+
 	for _, word := range words {
+		entries = append(entries, dict.Entry{Word: word})
+	}
+
+	return m.AddEntry(entries...)
+}
+
+func (m *MongoModel) AddEntry(entries ...dict.Entry) error {
+	for _, entry := range entries {
 		_, err := m.store.UpdateOne(context.Background(),
 			bson.D{
-				{"word", word},
+				bson.E{Key: "word", Value: entry.Word},
 			},
 			bson.D{
-				{"$set", bson.D{
-					{"word", word},
+				bson.E{Key: "$set", Value: bson.D{
+					bson.E{Key: "word", Value: entry.Word},
+					bson.E{Key: "categories", Value: entry.Categories},
+					bson.E{Key: "weight", Value: entry.Weight},
+					bson.E{Key: "replacement", Value: entry.Replacement},
+					bson.E{Key: "options", Value: entry.FormatOptions()},
 				}},
 			},
 			options.Update().SetUpsert(true),
@@ -256,12 +365,21 @@ func (m *MongoModel) AddWord(words ...string) error {
 			return err
 		}
 
-		m.addChan <- word
+		if entry.Source == "" {
+			entry.Source = m.source()
+		}
+
+		m.addChan <- entry
 	}
 
 	return nil
 }
 
+// source 数据源名称, 作为未指定来源的敏感词的来源
+func (m *MongoModel) source() string {
+	return "mongo:" + m.store.Name()
+}
+
 func (m *MongoModel) DelWord(words ...string) error {
 	for _, word := range words {
 		_, err := m.store.DeleteOne(context.Background(),
@@ -278,3 +396,122 @@ func (m *MongoModel) DelWord(words ...string) error {
 
 	return nil
 }
+
+func (m *MongoModel) LoadRulePath(paths ...string) error {
+	return loadRulePath(paths, m.loadRules)
+}
+
+func (m *MongoModel) LoadRuleHttp(urls ...string) error {
+	return loadRuleHttp(urls, m.loadRules)
+}
+
+func (m *MongoModel) LoadRules(reader io.Reader) error {
+	return m.loadRules(reader, m.ruleSource())
+}
+
+// loadRules 加载规则, source 记录规则的来源
+func (m *MongoModel) loadRules(reader io.Reader, source string) error {
+	rules, err := readRules(reader, source)
+	if err != nil {
+		return err
+	}
+
+	return m.AddRule(rules...)
+}
+
+func (m *MongoModel) ReadRules() []dict.Rule {
+	ctx := context.Background()
+	cur, err := m.rules.Find(ctx,
+		bson.D{},
+		options.Find().SetProjection(
+			bson.D{
+				bson.E{Key: "_id", Value: 0},
+				bson.E{Key: "name", Value: 1},
+				bson.E{Key: "expr", Value: 1},
+			},
+		),
+	)
+	if err != nil {
+		return nil
+	}
+
+	var rules []*ruleDoc
+
+	err = cur.All(ctx, &rules)
+	if err != nil {
+		return nil
+	}
+
+	res := make([]dict.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		res = append(res, dict.Rule{
+			Name: rule.Name,
+			Expr: rule.Expr,
+		})
+	}
+
+	return res
+}
+
+func (m *MongoModel) GetRuleAddChan() <-chan dict.Rule {
+	return m.ruleAddChan
+}
+
+func (m *MongoModel) GetRuleDelChan() <-chan string {
+	return m.ruleDelChan
+}
+
+func (m *MongoModel) AddRule(rules ...dict.Rule) error {
+	if err := checkRules(rules); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		_, err := m.rules.UpdateOne(context.Background(),
+			bson.D{
+				bson.E{Key: "name", Value: rule.Name},
+			},
+			bson.D{
+				bson.E{Key: "$set", Value: bson.D{
+					bson.E{Key: "name", Value: rule.Name},
+					bson.E{Key: "expr", Value: rule.Expr},
+				}},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+
+		if rule.Source == "" {
+			rule.Source = m.ruleSource()
+		}
+
+		m.ruleAddChan <- rule
+	}
+
+	return nil
+}
+
+// ruleSource 数据源名称, 作为未指定来源的规则的来源
+func (m *MongoModel) ruleSource() string {
+	return "mongo:" + m.rules.Name()
+}
+
+func (m *MongoModel) DelRule(names ...string) error {
+	for _, name := range names {
+		_, err := m.rules.DeleteOne(context.Background(),
+			bson.D{
+				bson.E{Key: "name", Value: name},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		m.ruleDelChan <- name
+	}
+
+	return nil
+}
@@ -8,31 +8,59 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/imroc/req/v3"
 	"github.com/jmoiron/sqlx"
+	"github.com/sgoware/go-sensitive/dict"
 	"io"
 	"net/http"
 	"os"
+	"strings"
 )
 
 const (
-	defaultTable = "dirties"
+	defaultTable     = "dirties"
+	defaultRuleTable = "dirty_rules"
 )
 
 type MysqlConfig struct {
-	Dsn       string
-	Database  string
-	TableName string
+	Dsn           string
+	Database      string
+	TableName     string
+	RuleTableName string
 }
 
 type Subject struct {
+	Id          int64   `db:"id"`
+	Word        string  `db:"word"`
+	Categories  string  `db:"categories"` // 以逗号分隔的分类
+	Weight      float64 `db:"weight"`
+	Replacement string  `db:"replacement"`
+	Options     string  `db:"options"` // 以逗号分隔的选项, 见 dict.Entry.ParseOptions
+}
+
+type RuleSubject struct {
 	Id   int64  `db:"id"`
-	Word string `db:"word"`
+	Name string `db:"name"`
+	Expr string `db:"expr"`
+}
+
+// 旧版本创建的表中可能缺少的字段
+var mysqlColumns = []struct {
+	name       string
+	definition string
+}{
+	{"categories", "varchar(255) NOT NULL DEFAULT ''"},
+	{"weight", "double NOT NULL DEFAULT 0"},
+	{"replacement", "varchar(255) NOT NULL DEFAULT ''"},
+	{"options", "varchar(255) NOT NULL DEFAULT ''"},
 }
 
 type MysqlModel struct {
-	store     *sqlx.DB
-	TableName string
-	addChan   chan string
-	delChan   chan string
+	store         *sqlx.DB
+	TableName     string
+	RuleTableName string
+	addChan       chan dict.Entry
+	delChan       chan string
+	ruleAddChan   chan dict.Rule
+	ruleDelChan   chan string
 }
 
 func NewMysqlModel(config *MysqlConfig) *MysqlModel {
@@ -64,19 +92,72 @@ func NewMysqlModel(config *MysqlConfig) *MysqlModel {
 		_, err = db.Exec(fmt.Sprintf("CREATE TABLE `%s` "+
 			"(`id` bigint(20) NOT NULL AUTO_INCREMENT, "+
 			"`word` varchar(255) NOT NULL, "+
+			"`categories` varchar(255) NOT NULL DEFAULT '', "+
+			"`weight` double NOT NULL DEFAULT 0, "+
+			"`replacement` varchar(255) NOT NULL DEFAULT '', "+
+			"`options` varchar(255) NOT NULL DEFAULT '', "+
 			"PRIMARY KEY (`id`) USING BTREE)",
 			config.TableName),
 		)
 		if err != nil {
 			return nil
 		}
+	} else {
+		for _, column := range mysqlColumns {
+			var columnName string
+
+			err = db.Get(
+				&columnName,
+				fmt.Sprintf(
+					"SELECT `COLUMN_NAME` FROM information_schema.columns WHERE table_schema = '%s' AND table_name = '%s' AND column_name = '%s' LIMIT 1",
+					config.Database,
+					config.TableName,
+					column.name),
+			)
+			if err != nil {
+				if err != sql.ErrNoRows {
+					return nil
+				}
+			}
+
+			if columnName == "" {
+				_, err = db.Exec(fmt.Sprintf("ALTER TABLE `%s` "+
+					"ADD COLUMN `%s` %s",
+					config.TableName,
+					column.name,
+					column.definition),
+				)
+				if err != nil {
+					return nil
+				}
+			}
+		}
+	}
+
+	if config.RuleTableName == "" {
+		config.RuleTableName = defaultRuleTable
+	}
+
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` "+
+		"(`id` bigint(20) NOT NULL AUTO_INCREMENT, "+
+		"`name` varchar(255) NOT NULL, "+
+		"`expr` text NOT NULL, "+
+		"PRIMARY KEY (`id`) USING BTREE, "+
+		"UNIQUE KEY `name` (`name`))",
+		config.RuleTableName),
+	)
+	if err != nil {
+		return nil
 	}
 
 	return &MysqlModel{
-		store:     db,
-		TableName: config.TableName,
-		addChan:   make(chan string),
-		delChan:   make(chan string),
+		store:         db,
+		TableName:     config.TableName,
+		RuleTableName: config.RuleTableName,
+		addChan:       make(chan dict.Entry),
+		delChan:       make(chan string),
+		ruleAddChan:   make(chan dict.Rule),
+		ruleDelChan:   make(chan string),
 	}
 }
 
@@ -91,7 +172,7 @@ func (m *MysqlModel) LoadDictPath(paths ...string) error {
 				return err
 			}
 
-			return m.LoadDict(f)
+			return m.loadDict(f, path)
 		}(path)
 		if err != nil {
 			return err
@@ -119,7 +200,7 @@ func (m *MysqlModel) LoadDictHttp(urls ...string) error {
 				_ = Body.Close()
 			}(httpRes.Body)
 
-			return m.LoadDict(httpRes.Body)
+			return m.loadDict(httpRes.Body, url)
 		}(url)
 		if err != nil {
 			return err
@@ -130,9 +211,13 @@ func (m *MysqlModel) LoadDictHttp(urls ...string) error {
 }
 
 func (m *MysqlModel) LoadDict(reader io.Reader) error {
+	return m.loadDict(reader, m.source())
+}
+
+// loadDict 加载字典, source 记录敏感词的来源
+func (m *MysqlModel) loadDict(reader io.Reader, source string) error {
 	buf := bufio.NewReader(reader)
-	var words []*Subject
-	set := make(map[string]struct{})
+	var entries []dict.Entry
 
 	for {
 		line, _, err := buf.ReadLine()
@@ -143,28 +228,30 @@ func (m *MysqlModel) LoadDict(reader io.Reader) error {
 			break
 		}
 
-		word := string(line)
+		entry := dict.ParseEntry(string(line))
+		entry.Source = source
 
-		if _, ok := set[word]; !ok {
-			words = append(words, &Subject{
-				Id:   0,
-				Word: word,
-			})
-			set[word] = struct{}{}
-		}
+		entries = append(entries, entry)
+	}
+
+	return m.AddEntry(entries...)
+}
 
-		m.addChan <- word
+// insert 插入敏感词, 并删除重复的敏感词, 只保留最后插入的一条
+func (m *MysqlModel) insert(words []*Subject) error {
+	if len(words) == 0 {
+		return nil
 	}
 
-	_, err := m.store.NamedExec(fmt.Sprintf("INSERT INTO `%s` (`word`) VALUES (:word)", m.TableName), words)
+	_, err := m.store.NamedExec(fmt.Sprintf("INSERT INTO `%s` (`word`, `categories`, `weight`, `replacement`, `options`) VALUES (:word, :categories, :weight, :replacement, :options)", m.TableName), words)
 	if err != nil {
 		return err
 	}
 
 	_, err = m.store.Exec(fmt.Sprintf("DELETE FROM `%s` AS t1 "+
 		"WHERE t1.`id` <> "+
-		"(SELECT t.minid FROM "+
-		"(SELECT MIN(t2.`id`) AS minid FROM `%s` AS t2 WHERE t1.`word` = t2.`word`) t )",
+		"(SELECT t.maxid FROM "+
+		"(SELECT MAX(t2.`id`) AS maxid FROM `%s` AS t2 WHERE t1.`word` = t2.`word`) t )",
 		m.TableName,
 		m.TableName),
 	)
@@ -206,7 +293,33 @@ func (m *MysqlModel) ReadString() []string {
 	return words
 }
 
-func (m *MysqlModel) GetAddChan() <-chan string {
+func (m *MysqlModel) ReadEntries() []dict.Entry {
+	var words []*Subject
+
+	err := m.store.Select(&words, fmt.Sprintf("SELECT `word`, `categories`, `weight`, `replacement`, `options` FROM `%s`", m.TableName))
+	if err != nil {
+		return nil
+	}
+
+	res := make([]dict.Entry, 0, len(words))
+
+	for _, word := range words {
+		entry := dict.Entry{
+			Word:        word.Word,
+			Categories:  dict.SplitCategories(word.Categories),
+			Weight:      word.Weight,
+			Replacement: word.Replacement,
+			Source:      m.source(),
+		}
+		entry.ParseOptions(word.Options)
+
+		res = append(res, entry)
+	}
+
+	return res
+}
+
+func (m *MysqlModel) GetAddChan() <-chan dict.Entry {
 	return m.addChan
 }
 
@@ -215,37 +328,52 @@ func (m *MysqlModel) GetDelChan() <-chan string {
 }
 
 func (m *MysqlModel) AddWord(words ...string) error {
-	insertedWords := make([]*Subject, 0, len(words))
-	set := make(map[string]struct{})
+	entries := make([]dict.Entry, 0, len(words))
 
 	for _, word := range words {
-		if _, ok := set[word]; !ok {
-			insertedWords = append(insertedWords, &Subject{
-				Id:   0,
-				Word: word,
-			})
-			set[word] = struct{}{}
-		}
+		entries = append(entries, dict.Entry{Word: word})
 	}
 
-	_, err := m.store.NamedExec(fmt.Sprintf("INSERT INTO `%s` (`word`) VALUES (:word)", m.TableName), insertedWords)
-	if err != nil {
-		return err
+	return m.AddEntry(entries...)
+}
+
+// AddEntry 添加敏感词, 重复的敏感词只保留最后一条, 写入数据库与发送给过滤器的条目相同
+func (m *MysqlModel) AddEntry(entries ...dict.Entry) error {
+	entries = lastEntries(entries)
+	insertedWords := make([]*Subject, 0, len(entries))
+
+	for _, entry := range entries {
+		insertedWords = append(insertedWords, &Subject{
+			Id:          0,
+			Word:        entry.Word,
+			Categories:  strings.Join(entry.Categories, ","),
+			Weight:      entry.Weight,
+			Replacement: entry.Replacement,
+			Options:     entry.FormatOptions(),
+		})
 	}
-	_, err = m.store.Exec(fmt.Sprintf("DELETE FROM `%s` AS t1 "+
-		"WHERE t1.`id` <> "+
-		"(SELECT t.minid FROM "+
-		"(SELECT MIN(t2.`id`) AS minid FROM `%s` AS t2 WHERE t1.`word` = t2.`word`) t )",
-		m.TableName,
-		m.TableName),
-	)
+
+	err := m.insert(insertedWords)
 	if err != nil {
 		return err
 	}
 
+	for _, entry := range entries {
+		if entry.Source == "" {
+			entry.Source = m.source()
+		}
+
+		m.addChan <- entry
+	}
+
 	return nil
 }
 
+// source 数据源名称, 作为未指定来源的敏感词的来源
+func (m *MysqlModel) source() string {
+	return "mysql:" + m.TableName
+}
+
 func (m *MysqlModel) DelWord(words ...string) error {
 	query, args, _ := sqlx.In(fmt.Sprintf("DELETE FROM `%s` WHERE `word` IN (?)", m.TableName), words)
 	_, err := m.store.Exec(query, args)
@@ -255,3 +383,114 @@ func (m *MysqlModel) DelWord(words ...string) error {
 
 	return nil
 }
+
+func (m *MysqlModel) LoadRulePath(paths ...string) error {
+	return loadRulePath(paths, m.loadRules)
+}
+
+func (m *MysqlModel) LoadRuleHttp(urls ...string) error {
+	return loadRuleHttp(urls, m.loadRules)
+}
+
+func (m *MysqlModel) LoadRules(reader io.Reader) error {
+	return m.loadRules(reader, m.ruleSource())
+}
+
+// loadRules 加载规则, source 记录规则的来源
+func (m *MysqlModel) loadRules(reader io.Reader, source string) error {
+	rules, err := readRules(reader, source)
+	if err != nil {
+		return err
+	}
+
+	return m.AddRule(rules...)
+}
+
+func (m *MysqlModel) ReadRules() []dict.Rule {
+	var rules []*RuleSubject
+
+	err := m.store.Select(&rules, fmt.Sprintf("SELECT `name`, `expr` FROM `%s`", m.RuleTableName))
+	if err != nil {
+		return nil
+	}
+
+	res := make([]dict.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		res = append(res, dict.Rule{
+			Name: rule.Name,
+			Expr: rule.Expr,
+		})
+	}
+
+	return res
+}
+
+func (m *MysqlModel) GetRuleAddChan() <-chan dict.Rule {
+	return m.ruleAddChan
+}
+
+func (m *MysqlModel) GetRuleDelChan() <-chan string {
+	return m.ruleDelChan
+}
+
+func (m *MysqlModel) AddRule(rules ...dict.Rule) error {
+	if err := checkRules(rules); err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	insertedRules := make([]*RuleSubject, 0, len(rules))
+
+	for _, rule := range rules {
+		insertedRules = append(insertedRules, &RuleSubject{
+			Name: rule.Name,
+			Expr: rule.Expr,
+		})
+	}
+
+	_, err := m.store.NamedExec(fmt.Sprintf("INSERT INTO `%s` (`name`, `expr`) VALUES (:name, :expr) "+
+		"ON DUPLICATE KEY UPDATE `expr` = VALUES(`expr`)", m.RuleTableName), insertedRules)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if rule.Source == "" {
+			rule.Source = m.ruleSource()
+		}
+
+		m.ruleAddChan <- rule
+	}
+
+	return nil
+}
+
+// ruleSource 数据源名称, 作为未指定来源的规则的来源
+func (m *MysqlModel) ruleSource() string {
+	return "mysql:" + m.RuleTableName
+}
+
+func (m *MysqlModel) DelRule(names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf("DELETE FROM `%s` WHERE `name` IN (?)", m.RuleTableName), names)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.store.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		m.ruleDelChan <- name
+	}
+
+	return nil
+}
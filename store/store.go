@@ -1,6 +1,18 @@
 package store
 
-import "io"
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/imroc/req/v3"
+
+	"github.com/sgoware/go-sensitive/dict"
+)
 
 type (
 	Store interface {
@@ -9,9 +21,139 @@ type (
 		LoadDict(reader io.Reader) error
 		ReadChan() <-chan string
 		ReadString() []string
-		GetAddChan() <-chan string
+		ReadEntries() []dict.Entry
+		GetAddChan() <-chan dict.Entry
 		GetDelChan() <-chan string
 		AddWord(words ...string) error
+		AddEntry(entries ...dict.Entry) error
 		DelWord(words ...string) error
+		RuleStore
+	}
+
+	// RuleStore 存储由敏感词组成的布尔规则, 规则文件每行的格式见 dict.ParseRule
+	RuleStore interface {
+		LoadRulePath(path ...string) error
+		LoadRuleHttp(url ...string) error
+		LoadRules(reader io.Reader) error
+		ReadRules() []dict.Rule
+		GetRuleAddChan() <-chan dict.Rule
+		GetRuleDelChan() <-chan string
+		AddRule(rules ...dict.Rule) error
+		DelRule(names ...string) error
 	}
 )
+
+// readRules 读取规则, 跳过空行与 # 开头的注释, 有任何一行无法解析时返回错误
+func readRules(reader io.Reader, source string) ([]dict.Rule, error) {
+	var res []dict.Rule
+
+	buf := bufio.NewReader(reader)
+	for lineNum := 1; ; lineNum++ {
+		line, _, err := buf.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		text := strings.TrimSpace(string(line))
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		rule, err := dict.ParseRule(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", source, lineNum, err)
+		}
+		rule.Source = source
+
+		res = append(res, rule)
+	}
+
+	return res, nil
+}
+
+// checkRules 检查规则名与表达式
+func checkRules(rules []dict.Rule) error {
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %q: empty name", rule.Expr)
+		}
+		if _, err := dict.CompileExpr(rule.Expr); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// lastEntries 去掉重复的敏感词, 每个敏感词只保留最后一次出现的条目, 与过滤器中后加入的条目覆盖先加入的条目一致
+func lastEntries(entries []dict.Entry) []dict.Entry {
+	res := make([]dict.Entry, 0, len(entries))
+	set := make(map[string]struct{})
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if _, ok := set[entries[i].Word]; !ok {
+			res = append(res, entries[i])
+			set[entries[i].Word] = struct{}{}
+		}
+	}
+
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+
+	return res
+}
+
+// loadRulePath 从文件中加载规则, load 的第二个参数为规则的来源
+func loadRulePath(paths []string, load func(reader io.Reader, source string) error) error {
+	for _, path := range paths {
+		err := func(path string) error {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func(f *os.File) {
+				_ = f.Close()
+			}(f)
+
+			return load(f, path)
+		}(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadRuleHttp 从 url 加载规则, load 的第二个参数为规则的来源
+func loadRuleHttp(urls []string, load func(reader io.Reader, source string) error) error {
+	for _, url := range urls {
+		err := func(url string) error {
+			httpRes, err := req.Get(url)
+			if err != nil {
+				return err
+			}
+			if httpRes == nil {
+				return errors.New("nil http response")
+			}
+			if httpRes.StatusCode != http.StatusOK {
+				return errors.New(httpRes.GetStatus())
+			}
+
+			defer func(Body io.ReadCloser) {
+				_ = Body.Close()
+			}(httpRes.Body)
+
+			return load(httpRes.Body, url)
+		}(url)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
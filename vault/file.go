@@ -0,0 +1,93 @@
+package vault
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+type record struct {
+	Token string `json:"token"`
+	Text  string `json:"text"`
+}
+
+// FileModel 以 JSON Lines 格式追加写入文件, 打开时将已有记录加载到内存
+type FileModel struct {
+	mu    sync.RWMutex
+	file  *os.File
+	store map[string]string
+}
+
+func NewFileModel(path string) (*FileModel, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &FileModel{
+		file:  f,
+		store: make(map[string]string),
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		var r record
+
+		err = json.Unmarshal(scanner.Bytes(), &r)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+
+		m.store[r.Token] = r.Text
+	}
+	if err = scanner.Err(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *FileModel) Put(token, text string) error {
+	line, err := json.Marshal(record{Token: token, Text: text})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err = m.file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	err = m.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	m.store[token] = text
+
+	return nil
+}
+
+func (m *FileModel) Get(token string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	text, ok := m.store[token]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return text, nil
+}
+
+func (m *FileModel) Close() error {
+	return m.file.Close()
+}
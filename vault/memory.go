@@ -0,0 +1,30 @@
+package vault
+
+import (
+	cmap "github.com/orcaman/concurrent-map/v2"
+)
+
+type MemoryModel struct {
+	store cmap.ConcurrentMap[string, string]
+}
+
+func NewMemoryModel() *MemoryModel {
+	return &MemoryModel{
+		store: cmap.New[string](),
+	}
+}
+
+func (m *MemoryModel) Put(token, text string) error {
+	m.store.Set(token, text)
+
+	return nil
+}
+
+func (m *MemoryModel) Get(token string) (string, error) {
+	text, ok := m.store.Get(token)
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return text, nil
+}
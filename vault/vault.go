@@ -0,0 +1,15 @@
+package vault
+
+import "errors"
+
+// ErrNotFound 令牌不存在
+var ErrNotFound = errors.New("token not found")
+
+type (
+	// Vault 保存脱敏令牌与原文的对应关系, 用于授权审计人员还原原文
+	Vault interface {
+		Put(token, text string) error
+		// Get 返回令牌对应的原文, 令牌不存在时返回 ErrNotFound
+		Get(token string) (string, error)
+	}
+)